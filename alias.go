@@ -0,0 +1,119 @@
+package configo
+
+// keyAlias maps an old or alternative key onto its canonical key.
+type keyAlias struct {
+	key        string
+	deprecated bool
+	message    string
+}
+
+// WithAlias makes alias an alternative spelling of key. A value set under
+// alias, or nested below it, populates key instead. If both are set, in one
+// provider or in different layers, key wins and an AliasConflictError is
+// passed to the warning handler.
+func WithAlias(alias, key string) Option {
+	return func(c *Config) {
+		c.addAlias(alias, keyAlias{key: key})
+	}
+}
+
+// WithDeprecated is like WithAlias, but every use of old is also reported
+// as a DeprecatedKeyError, with message explaining the migration, through
+// the warning handler.
+func WithDeprecated(old, replacement, message string) Option {
+	return func(c *Config) {
+		c.addAlias(old, keyAlias{key: replacement, deprecated: true, message: message})
+	}
+}
+
+func (c *Config) addAlias(alias string, a keyAlias) {
+	if c.aliases == nil {
+		c.aliases = make(map[string]keyAlias)
+	}
+	c.aliases[alias] = a
+}
+
+// resolveAlias returns the canonical key for a key under an alias, and the
+// alias it matched. The longest matching alias wins.
+func (c *Config) resolveAlias(key string) (string, keyAlias, bool) {
+	var match string
+	found := false
+	for old := range c.aliases {
+		if inSubtree(key, old) && (!found || len(old) > len(match)) {
+			match, found = old, true
+		}
+	}
+	if !found {
+		return key, keyAlias{}, false
+	}
+	a := c.aliases[match]
+	return a.key + key[len(match):], a, true
+}
+
+// applyAliases moves the keys of one flattened layer from their aliases to
+// their canonical keys, reporting deprecated and conflicting uses, and
+// renames its list keys to match. It returns the original key of every moved key.
+func (c *Config) applyAliases(flat map[string]any, lists map[string]bool, source func(string, any) Source) map[string]string {
+	if len(c.aliases) == 0 {
+		return nil
+	}
+	for k := range lists {
+		if nk, _, ok := c.resolveAlias(k); ok {
+			delete(lists, k)
+			lists[nk] = true
+		}
+	}
+	renamed := make(map[string]string)
+	for _, k := range sortedKeys(flat) {
+		nk, a, ok := c.resolveAlias(k)
+		if !ok {
+			continue
+		}
+		v := flat[k]
+		delete(flat, k)
+		if a.deprecated {
+			c.warn(&DeprecatedKeyError{Key: k, Replacement: nk, Message: a.message, Source: source(k, v)})
+		}
+		if cur, ok := flat[nk]; ok {
+			c.warn(&AliasConflictError{Alias: k, Key: nk, Source: source(k, v), KeySource: source(nk, cur)})
+			continue
+		}
+		flat[nk] = v
+		renamed[nk] = k
+	}
+	return renamed
+}
+
+// aliasedLayer is a flattened layer whose aliases have been applied.
+type aliasedLayer struct {
+	flat    map[string]any
+	lists   map[string]bool
+	renamed map[string]string
+	source  func(string, any) Source
+}
+
+// dropAliasConflicts removes every key a layer set through an alias when
+// another layer sets the canonical key itself, so that the canonical key
+// wins regardless of layer order.
+func (c *Config) dropAliasConflicts(layers []aliasedLayer) {
+	direct := make(map[string]Source)
+	for _, l := range layers {
+		for k, v := range l.flat {
+			if _, ok := l.renamed[k]; !ok {
+				direct[k] = l.source(k, v)
+			}
+		}
+	}
+	for _, l := range layers {
+		for _, nk := range sortedKeys(l.renamed) {
+			src, ok := direct[nk]
+			if !ok {
+				continue
+			}
+			old := l.renamed[nk]
+			c.warn(&AliasConflictError{Alias: old, Key: nk, Source: l.source(old, l.flat[nk]), KeySource: src})
+			delete(l.flat, nk)
+			delete(l.renamed, nk)
+		}
+	}
+}
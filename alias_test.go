@@ -0,0 +1,153 @@
+package configo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAliasPopulatesCanonicalKey(t *testing.T) {
+	var warnings []error
+	cfg := New(
+		WithAlias("db", "database"),
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }),
+		WithDefaults(map[string]any{"db": map[string]any{"host": "db.internal", "port": 5432}}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := MustGet[string](cfg, "database.host"); got != "db.internal" {
+		t.Errorf("database.host = %q, want db.internal", got)
+	}
+	if got := MustGet[int](cfg, "database.port"); got != 5432 {
+		t.Errorf("database.port = %d, want 5432", got)
+	}
+	if _, ok := cfg.Data()["db.host"]; ok {
+		t.Error("alias key should not remain in data")
+	}
+	if len(warnings) != 0 {
+		t.Errorf("plain alias should not warn, got %v", warnings)
+	}
+}
+
+func TestDeprecatedKeyWarns(t *testing.T) {
+	var warnings []error
+	cfg := New(
+		WithDeprecated("server.addr", "server.host", "removed in v3"),
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }),
+		WithDefaults(map[string]any{"server": map[string]any{"addr": "0.0.0.0"}}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := MustGet[string](cfg, "server.host"); got != "0.0.0.0" {
+		t.Errorf("server.host = %q, want 0.0.0.0", got)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", warnings)
+	}
+	var de *DeprecatedKeyError
+	if !errors.As(warnings[0], &de) {
+		t.Fatalf("expected DeprecatedKeyError, got %T", warnings[0])
+	}
+	if de.Key != "server.addr" || de.Replacement != "server.host" || de.Message != "removed in v3" {
+		t.Errorf("unexpected warning %+v", de)
+	}
+	if de.Source.Provider != "defaults" {
+		t.Errorf("Source.Provider = %q, want defaults", de.Source.Provider)
+	}
+}
+
+func TestAliasConflictNewKeyWins(t *testing.T) {
+	var warnings []error
+	cfg := New(
+		WithDeprecated("server.addr", "server.host", ""),
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }),
+		WithDefaults(map[string]any{"server": map[string]any{"addr": "old", "host": "new"}}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := MustGet[string](cfg, "server.host"); got != "new" {
+		t.Errorf("server.host = %q, want new", got)
+	}
+	var ce *AliasConflictError
+	found := false
+	for _, w := range warnings {
+		if errors.As(w, &ce) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected AliasConflictError, got %v", warnings)
+	}
+}
+
+func TestAliasConflictAcrossLayers(t *testing.T) {
+	t.Setenv("PX2_SERVER_ADDR", "old")
+	var warnings []error
+	cfg := New(
+		WithDeprecated("server.addr", "server.host", ""),
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }),
+		WithDefaults(map[string]any{"server": map[string]any{"host": "new"}}),
+		WithEnvPrefix("PX2"),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := MustGet[string](cfg, "server.host"); got != "new" {
+		t.Errorf("server.host = %q, want new", got)
+	}
+	if src, ok := cfg.Source("server.host"); !ok || src.Provider != "defaults" {
+		t.Errorf("Source = %v, want defaults", src)
+	}
+	var ce *AliasConflictError
+	for _, w := range warnings {
+		if errors.As(w, &ce) {
+			break
+		}
+	}
+	if ce == nil {
+		t.Fatalf("expected AliasConflictError, got %v", warnings)
+	}
+	if ce.Source.Location != "PX2_SERVER_ADDR" || ce.KeySource.Provider != "defaults" {
+		t.Errorf("unexpected conflict %+v", ce)
+	}
+}
+
+func TestAliasInHigherLayer(t *testing.T) {
+	t.Setenv("APP_SERVER_ADDR", "from-env")
+	cfg := New(
+		WithDeprecated("server.addr", "server.host", ""),
+		WithWarningHandler(func(error) {}),
+		WithDefaults(map[string]any{"server": map[string]any{"port": 80}}),
+		WithEnvPrefix("APP"),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := MustGet[string](cfg, "server.host"); got != "from-env" {
+		t.Errorf("server.host = %q, want from-env", got)
+	}
+	src, ok := cfg.Source("server.host")
+	if !ok || src.Location != "APP_SERVER_ADDR" {
+		t.Errorf("Source = %v, want env (APP_SERVER_ADDR)", src)
+	}
+}
+
+func TestSetThroughAlias(t *testing.T) {
+	cfg := New(WithAlias("db.host", "database.host"))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("db.host", "override"); err != nil {
+		t.Fatal(err)
+	}
+	if got := MustGet[string](cfg, "database.host"); got != "override" {
+		t.Errorf("database.host = %q, want override", got)
+	}
+}
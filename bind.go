@@ -8,15 +8,21 @@ import (
 )
 
 // Bind populates a struct from config values using `config` and `default` struct tags.
+// All fields are read from the same snapshot, even if a reload runs concurrently.
 func (c *Config) Bind(target any) error {
+	return c.Snapshot().Bind(target)
+}
+
+// Bind populates a struct from the snapshot, like Config.Bind.
+func (s *Snapshot) Bind(target any) error {
 	v := reflect.ValueOf(target)
 	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
 		return fmt.Errorf("bind: target must be a pointer to a struct")
 	}
-	return c.bindStruct(v.Elem())
+	return s.bindStruct(v.Elem())
 }
 
-func (c *Config) bindStruct(v reflect.Value) error {
+func (s *Snapshot) bindStruct(v reflect.Value) error {
 	t := v.Type()
 	for i := range t.NumField() {
 		field := t.Field(i)
@@ -28,7 +34,7 @@ func (c *Config) bindStruct(v reflect.Value) error {
 
 		// Handle embedded/nested structs
 		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Duration(0)) {
-			if err := c.bindStruct(fv); err != nil {
+			if err := s.bindStruct(fv); err != nil {
 				return err
 			}
 			continue
@@ -39,9 +45,7 @@ func (c *Config) bindStruct(v reflect.Value) error {
 			continue
 		}
 
-		c.mu.RLock()
-		val, ok := c.data[key]
-		c.mu.RUnlock()
+		val, ok := s.Lookup(key)
 
 		if !ok {
 			defStr := field.Tag.Get("default")
@@ -1,8 +1,14 @@
 package configo
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"os"
+	"path/filepath"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/devaloi/configo/provider"
@@ -11,12 +17,46 @@ import (
 
 // Config holds merged configuration data and providers.
 type Config struct {
-	mu        sync.RWMutex
-	data      map[string]any
+	root   *Config // parent of a Sub view; nil for a root Config
+	prefix string
+
+	snap      atomic.Pointer[Snapshot]
+	version   uint64       // last published version; guarded by writeMu
+	writeMu   sync.Mutex   // serializes Load and override changes
+	mu        sync.RWMutex // guards the subscriber lists and status
 	providers []provider.Provider
-	filePath  string
 	onChange  []func(*Config)
-	watcher   *watcher.Watcher
+	onDiff    []func(Diff)
+	watcher   watcher.Backend
+	stopWatch context.CancelFunc // stops provider.NotifyingProvider watches
+
+	validators   []func(*Snapshot) error
+	beforeChange []func(*Snapshot) error
+
+	onReloadError []func(error)
+	status        Status
+
+	optErrs    []error
+	profiles   []string
+	profileEnv string
+
+	strategies      map[string]MergeStrategy
+	defaultMerge    MergeStrategy
+	noInterpolation bool
+	resolvers       map[string]Resolver
+	decryptKey      keySource
+	providerTimeout time.Duration
+	loadPolicy      LoadPolicy
+	watchBackend    watcher.Backend
+	normalizer      KeyNormalizer
+	strictKeys      bool
+	aliases         map[string]keyAlias
+	onWarning       func(error)
+
+	loaded    []loadedLayer         // layers of the last successful commit
+	lastGood  map[int][]loadedLayer // per declared provider, for LastKnownGood
+	overrides map[string]any
+	masked    map[string]bool
 }
 
 // Option configures a Config instance.
@@ -25,11 +65,27 @@ type Option func(*Config)
 // New creates a new Config with the given options.
 func New(opts ...Option) *Config {
 	c := &Config{
-		data: make(map[string]any),
+		resolvers:  defaultResolvers(),
+		normalizer: LowercaseKeys,
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	if len(c.strategies) > 0 {
+		strategies := make(map[string]MergeStrategy, len(c.strategies))
+		for k, s := range c.strategies {
+			strategies[c.normalizer.apply(k)] = s
+		}
+		c.strategies = strategies
+	}
+	if len(c.aliases) > 0 {
+		aliases := make(map[string]keyAlias, len(c.aliases))
+		for k, a := range c.aliases {
+			a.key = c.normalizer.apply(a.key)
+			aliases[c.normalizer.apply(k)] = a
+		}
+		c.aliases = aliases
+	}
 	return c
 }
 
@@ -40,22 +96,29 @@ func WithDefaults(defaults map[string]any) Option {
 	}
 }
 
-// WithFile adds a file provider based on extension (.yaml/.yml, .json, .toml).
+// WithFile adds a file provider based on extension (.yaml/.yml, .json, .toml, .env).
+// Load fails if the file is missing or its extension is not supported.
 func WithFile(path string) Option {
 	return func(c *Config) {
-		c.filePath = path
-		var p provider.Provider
-		switch {
-		case hasExt(path, ".yaml", ".yml"):
-			p = provider.NewYAML(path)
-		case hasExt(path, ".json"):
-			p = provider.NewJSON(path)
-		case hasExt(path, ".toml"):
-			p = provider.NewTOML(path)
+		p := provider.ForPath(path)
+		if p == nil {
+			c.optErrs = append(c.optErrs, &UnsupportedFormatError{Path: path})
+			return
 		}
-		if p != nil {
-			c.providers = append(c.providers, p)
+		c.providers = append(c.providers, &profileFile{Provider: p, path: path})
+	}
+}
+
+// WithOptionalFile is like WithFile but a missing file loads as empty.
+// Parse errors and unsupported extensions still fail Load.
+func WithOptionalFile(path string) Option {
+	return func(c *Config) {
+		p := provider.ForPath(path)
+		if p == nil {
+			c.optErrs = append(c.optErrs, &UnsupportedFormatError{Path: path})
+			return
 		}
+		c.providers = append(c.providers, &profileFile{Provider: provider.NewOptional(p), path: path})
 	}
 }
 
@@ -73,6 +136,14 @@ func WithDotEnv(path string) Option {
 	}
 }
 
+// WithOptionalDotEnv adds a .env file provider that is skipped when the
+// file does not exist, as is usual in CI and production.
+func WithOptionalDotEnv(path string) Option {
+	return func(c *Config) {
+		c.providers = append(c.providers, provider.NewOptional(provider.NewDotEnv(path)))
+	}
+}
+
 // WithFlags adds a flag provider using the given FlagSet.
 func WithFlags(fs *flag.FlagSet) Option {
 	return func(c *Config) {
@@ -80,6 +151,23 @@ func WithFlags(fs *flag.FlagSet) Option {
 	}
 }
 
+// WithDir adds every .yaml/.yml, .json, .toml and .env file in dir, in
+// lexical order. Each file is its own layer, so 20-overrides.toml overrides
+// 10-base.yaml.
+func WithDir(dir string) Option {
+	return func(c *Config) {
+		c.providers = append(c.providers, provider.NewDir(dir))
+	}
+}
+
+// WithFiles adds every supported file matching a glob pattern such as
+// "config/*.yaml", in lexical order, each as its own layer.
+func WithFiles(pattern string) Option {
+	return func(c *Config) {
+		c.providers = append(c.providers, provider.NewFiles(pattern))
+	}
+}
+
 // WithProvider adds a custom provider.
 func WithProvider(p provider.Provider) Option {
 	return func(c *Config) {
@@ -87,80 +175,234 @@ func WithProvider(p provider.Provider) Option {
 	}
 }
 
+// WithWatcher sets the backend Watch uses, such as
+// watcher.Poll(2*time.Second) for network file systems where file system
+// notifications are unreliable. The default is a watcher.Watcher with a
+// 500ms debounce. A backend serves a single Watch call.
+func WithWatcher(b watcher.Backend) Option {
+	return func(c *Config) {
+		c.watchBackend = b
+	}
+}
+
 // Load iterates all providers in order and merges their data.
 // Later providers override earlier ones.
 func (c *Config) Load() error {
-	merged := make(map[string]any)
-	for _, p := range c.providers {
-		m, err := p.Load()
+	return c.LoadContext(context.Background())
+}
+
+// commit merges loaded with the override layer, resolves encrypted values,
+// secrets and placeholders, and publishes the result once validation and
+// OnBeforeChange subscribers accept it. Nothing changes if any step fails.
+// The caller must hold writeMu.
+func (c *Config) commit(loaded []loadedLayer) error {
+	layers := make([]aliasedLayer, len(loaded))
+	for i, l := range loaded {
+		name := providerName(l.provider)
+		source := func(k string, v any) Source {
+			return Source{Provider: name, Location: describeKey(l.provider, k), Layer: i, Value: v}
+		}
+		flat, lists, err := c.flattenLayer(l.data, source)
 		if err != nil {
 			return err
 		}
-		flat := Flatten(m)
-		for k, v := range flat {
-			merged[k] = v
+		renamed := c.applyAliases(flat, lists, source)
+		layers[i] = aliasedLayer{flat: flat, lists: lists, renamed: renamed, source: source}
+	}
+	if len(c.aliases) > 0 {
+		c.dropAliasConflicts(layers)
+	}
+
+	merged := make(map[string]any)
+	sources := make(map[string][]Source)
+	for _, l := range layers {
+		c.mergeLayer(merged, sources, l.flat, l.lists, func(k string, v any) Source {
+			if old, ok := l.renamed[k]; ok {
+				return l.source(old, v)
+			}
+			return l.source(k, v)
+		})
+	}
+	c.applyOverrides(merged, sources, len(loaded))
+	if err := c.checkConflicts(merged, sources); err != nil {
+		return err
+	}
+
+	sensitive := make(map[string]bool)
+	if err := c.decryptValues(merged, sources, sensitive); err != nil {
+		return err
+	}
+	if err := c.resolveSecrets(merged, sensitive); err != nil {
+		return err
+	}
+	if !c.noInterpolation {
+		var err error
+		if merged, err = interpolate(merged, sensitive, c.normalizer); err != nil {
+			return err
 		}
 	}
-	c.mu.Lock()
-	c.data = merged
-	c.mu.Unlock()
+	next := &Snapshot{
+		version:   c.version + 1,
+		data:      merged,
+		sources:   sources,
+		sensitive: sensitive,
+		parents:   parentKeys(merged),
+		normalize: c.normalizer,
+	}
+	if err := c.check(next); err != nil {
+		return err
+	}
+	c.version++
+	c.snap.Store(next)
+	c.loaded = loaded
 	return nil
 }
 
 // Data returns a copy of the current configuration data.
 func (c *Config) Data() map[string]any {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	out := make(map[string]any, len(c.data))
-	for k, v := range c.data {
-		out[k] = v
-	}
-	return out
+	return c.Snapshot().Data()
 }
 
 // OnChange registers a callback that fires when config is reloaded.
 func (c *Config) OnChange(fn func(*Config)) {
+	if c.root != nil {
+		c.root.OnChange(func(*Config) { fn(c) })
+		return
+	}
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.onChange = append(c.onChange, fn)
 }
 
-// Watch starts watching the config file for changes.
-// On change, it reloads and notifies all OnChange subscribers.
+// Watch starts watching every file-backed provider, and every provider
+// that detects its own changes, in the background. Changes that arrive
+// close together are coalesced into one reload, after which all OnChange
+// subscribers are notified.
 func (c *Config) Watch() error {
-	if c.filePath == "" {
+	if c.root != nil {
+		return c.root.Watch()
+	}
+	paths, notifiers := c.watchSources()
+	if len(paths) == 0 && len(notifiers) == 0 {
 		return nil
 	}
-	w := watcher.New(c.filePath, 500*time.Millisecond)
+	w := c.watchBackend
+	if w == nil {
+		w = watcher.New("", 500*time.Millisecond)
+	}
+	for _, path := range paths {
+		if err := w.Add(path); err != nil {
+			return err
+		}
+	}
 	w.OnChange(func() {
-		if err := c.Load(); err != nil {
-			return
+		old := c.snap.Load()
+		err := c.Load()
+		if err != nil {
+			c.reloadFailed(err)
 		}
-		c.mu.RLock()
-		handlers := make([]func(*Config), len(c.onChange))
-		copy(handlers, c.onChange)
-		c.mu.RUnlock()
-		for _, fn := range handlers {
-			fn(c)
+		var le *LoadError
+		if err != nil && !errors.As(err, &le) {
+			return
 		}
+		c.notify(old)
 	})
+	if err := w.Start(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for _, n := range notifiers {
+		go func() {
+			if err := n.Watch(ctx, w.Trigger); err != nil && ctx.Err() == nil {
+				c.reloadFailed(err)
+			}
+		}()
+	}
 	c.watcher = w
-	return w.Start()
+	c.stopWatch = cancel
+	return nil
+}
+
+// watchSources returns the watchable paths of every provider that implements
+// provider.WatchableProvider, including profile overlays, and every
+// provider that implements provider.NotifyingProvider.
+func (c *Config) watchSources() ([]string, []provider.NotifyingProvider) {
+	var (
+		paths     []string
+		notifiers []provider.NotifyingProvider
+		seen      = make(map[string]bool)
+	)
+	add := func(p provider.Provider) {
+		w, ok := p.(provider.WatchableProvider)
+		if !ok {
+			return
+		}
+		for _, path := range w.Paths() {
+			if seen[path] {
+				continue
+			}
+			// A missing file is watched for creation if its directory exists.
+			if _, err := os.Stat(path); err != nil {
+				if _, err := os.Stat(filepath.Dir(path)); err != nil {
+					continue
+				}
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	profiles := c.Profiles()
+	for _, p := range c.providers {
+		add(p)
+		if pf, ok := p.(*profileFile); ok {
+			for _, o := range pf.overlays(profiles) {
+				add(o)
+			}
+		}
+		if cp, ok := p.(*contextProvider); ok {
+			if n, ok := cp.p.(provider.NotifyingProvider); ok {
+				notifiers = append(notifiers, n)
+			}
+		} else if n, ok := p.(provider.NotifyingProvider); ok {
+			notifiers = append(notifiers, n)
+		}
+	}
+	return paths, notifiers
+}
+
+// notify calls every OnChange subscriber, then every OnChangeDiff
+// subscriber with the difference from old if anything changed.
+func (c *Config) notify(old *Snapshot) {
+	c.mu.RLock()
+	handlers := slices.Clone(c.onChange)
+	diffHandlers := slices.Clone(c.onDiff)
+	c.mu.RUnlock()
+	for _, fn := range handlers {
+		fn(c)
+	}
+	if len(diffHandlers) == 0 {
+		return
+	}
+	d := diffSnapshots(old, c.snap.Load())
+	if d.Empty() {
+		return
+	}
+	for _, fn := range diffHandlers {
+		fn(d)
+	}
 }
 
 // StopWatch stops the file watcher.
 func (c *Config) StopWatch() error {
+	if c.root != nil {
+		return c.root.StopWatch()
+	}
+	if c.stopWatch != nil {
+		c.stopWatch()
+	}
 	if c.watcher != nil {
 		return c.watcher.Stop()
 	}
 	return nil
 }
-
-func hasExt(path string, exts ...string) bool {
-	for _, ext := range exts {
-		if len(path) > len(ext) && path[len(path)-len(ext):] == ext {
-			return true
-		}
-	}
-	return false
-}
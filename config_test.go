@@ -1,7 +1,10 @@
 package configo
 
 import (
+	"errors"
 	"flag"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -87,3 +90,82 @@ func TestConfigDefaults(t *testing.T) {
 		t.Errorf("app.name = %v, want testapp", got)
 	}
 }
+
+func TestConfigOptionalFileMissing(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"server.host": "defaulthost"}),
+		WithOptionalFile("testdata/missing.yaml"),
+		WithOptionalDotEnv("testdata/missing.env"),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Data()["server.host"]; got != "defaulthost" {
+		t.Errorf("server.host = %v, want defaulthost", got)
+	}
+}
+
+func TestConfigOptionalFileParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(path, []byte("server: [unclosed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := New(WithOptionalFile(path))
+	if err := cfg.Load(); err == nil {
+		t.Fatal("expected parse error for existing optional file")
+	}
+}
+
+func TestConfigRequiredFileMissing(t *testing.T) {
+	cfg := New(WithFile("testdata/missing.yaml"))
+	if err := cfg.Load(); err == nil {
+		t.Fatal("expected error for missing required file")
+	}
+}
+
+func TestConfigUnsupportedFormat(t *testing.T) {
+	cfg := New(WithFile("testdata/config.ymal"))
+	err := cfg.Load()
+	var ufe *UnsupportedFormatError
+	if !errors.As(err, &ufe) {
+		t.Fatalf("expected UnsupportedFormatError, got %v", err)
+	}
+	if ufe.Path != "testdata/config.ymal" {
+		t.Errorf("Path = %q, want testdata/config.ymal", ufe.Path)
+	}
+}
+
+func TestConfigDirLayers(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"server.host": "defaulthost"}),
+		WithDir("testdata/conf.d"),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := cfg.Data()
+	if got := data["server.host"]; got != "localhost" {
+		t.Errorf("server.host = %v, want localhost", got)
+	}
+	if got := data["server.port"]; got != int64(9090) {
+		t.Errorf("server.port = %v, want 9090", got)
+	}
+
+	src, ok := cfg.Source("server.port")
+	if !ok || src.Location != filepath.Join("testdata", "conf.d", "20-overrides.toml") {
+		t.Errorf("source = %v, want toml (testdata/conf.d/20-overrides.toml)", src)
+	}
+	if n := len(cfg.Explain("server.port")); n != 2 {
+		t.Errorf("expected 2 layers for server.port, got %d", n)
+	}
+}
+
+func TestConfigFilesGlob(t *testing.T) {
+	cfg := New(WithFiles("testdata/conf.d/*.yaml"))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Data()["server.port"]; got != 8080 {
+		t.Errorf("server.port = %v, want 8080", got)
+	}
+}
@@ -0,0 +1,155 @@
+package configo
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const encV1Prefix = "enc:v1:AES256GCM:"
+
+// WithDecryptionKeyFile reads the key for enc:v1: values from a file holding a
+// base64-encoded 32-byte AES key.
+func WithDecryptionKeyFile(path string) Option {
+	return func(c *Config) {
+		c.decryptKey = keySource{path: path}
+	}
+}
+
+// WithDecryptionKeyEnv reads the key for enc:v1: values from an environment
+// variable holding a base64-encoded 32-byte AES key.
+func WithDecryptionKeyEnv(name string) Option {
+	return func(c *Config) {
+		c.decryptKey = keySource{env: name}
+	}
+}
+
+// Encrypt encrypts plaintext with AES-256-GCM under a 32-byte key and
+// returns a value of the form enc:v1:AES256GCM:<base64> that Load decrypts.
+func Encrypt(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encV1Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt.
+func Decrypt(key []byte, value string) (string, error) {
+	payload, ok := strings.CutPrefix(value, encV1Prefix)
+	if !ok {
+		return "", fmt.Errorf("unsupported encrypted value format")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", fmt.Errorf("decode: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// keySource locates the decryption key: a file path or an env var name.
+type keySource struct {
+	path string
+	env  string
+}
+
+func (k keySource) String() string {
+	switch {
+	case k.env != "":
+		return "$" + k.env
+	case k.path != "":
+		return k.path
+	default:
+		return "(none)"
+	}
+}
+
+func (k keySource) load() ([]byte, error) {
+	var encoded string
+	switch {
+	case k.path != "":
+		data, err := os.ReadFile(k.path)
+		if err != nil {
+			return nil, err
+		}
+		encoded = string(data)
+	case k.env != "":
+		v, ok := os.LookupEnv(k.env)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", k.env)
+		}
+		encoded = v
+	default:
+		return nil, fmt.Errorf("no decryption key configured")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decode key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// decryptValues replaces every enc:v1:AES256GCM: value in data with its
+// plaintext and marks the key as sensitive. The key is only read if such a
+// value exists; other strings starting with enc: are left alone.
+func (c *Config) decryptValues(data map[string]any, sources map[string][]Source, sensitive map[string]bool) error {
+	var key []byte
+	for k, v := range data {
+		s, ok := v.(string)
+		if !ok || !strings.HasPrefix(s, encV1Prefix) {
+			continue
+		}
+		var origin string
+		if layers := sources[k]; len(layers) > 0 {
+			origin = layers[len(layers)-1].String()
+		}
+		if key == nil {
+			var err error
+			if key, err = c.decryptKey.load(); err != nil {
+				return &DecryptionError{Key: k, KeySource: c.decryptKey.String(), Provider: origin, Err: err}
+			}
+		}
+		plain, err := Decrypt(key, s)
+		if err != nil {
+			return &DecryptionError{Key: k, KeySource: c.decryptKey.String(), Provider: origin, Err: err}
+		}
+		data[k] = plain
+		sensitive[k] = true
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package configo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey() []byte {
+	return bytes.Repeat([]byte{7}, 32)
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	enc, err := Encrypt(testKey(), "hunter2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(enc, "enc:v1:AES256GCM:") {
+		t.Errorf("unexpected format: %s", enc)
+	}
+	plain, err := Decrypt(testKey(), enc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plain != "hunter2" {
+		t.Errorf("Decrypt = %q, want hunter2", plain)
+	}
+	if _, err := Decrypt(bytes.Repeat([]byte{8}, 32), enc); err == nil {
+		t.Error("expected error decrypting with the wrong key")
+	}
+}
+
+func TestConfigDecryptsValues(t *testing.T) {
+	enc, err := Encrypt(testKey(), "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "config.key")
+	if err := os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(testKey())+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("database:\n  password: \""+enc+"\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := New(WithFile(cfgPath), WithDecryptionKeyFile(keyPath))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := MustGet[string](cfg, "database.password"); got != "hunter2" {
+		t.Errorf("database.password = %q, want hunter2", got)
+	}
+	if !cfg.IsSensitive("database.password") {
+		t.Error("decrypted value should be sensitive")
+	}
+
+	t.Setenv("CONFIGO_TEST_KEY", base64.StdEncoding.EncodeToString(testKey()))
+	cfg = New(WithFile(cfgPath), WithDecryptionKeyEnv("CONFIGO_TEST_KEY"))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error with env key: %v", err)
+	}
+}
+
+func TestConfigDecryptionError(t *testing.T) {
+	enc, err := Encrypt(testKey(), "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIGO_TEST_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{9}, 32)))
+
+	cfg := New(
+		WithDefaults(map[string]any{"database.password": enc}),
+		WithDecryptionKeyEnv("CONFIGO_TEST_KEY"),
+	)
+	err = cfg.Load()
+	var de *DecryptionError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecryptionError, got %v", err)
+	}
+	if de.Key != "database.password" || de.KeySource != "$CONFIGO_TEST_KEY" || de.Provider != "defaults" {
+		t.Errorf("DecryptionError = %+v", de)
+	}
+
+	cfg = New(WithDefaults(map[string]any{"database.password": enc}))
+	if err := cfg.Load(); !errors.As(err, &de) {
+		t.Fatalf("expected DecryptionError without a key, got %v", err)
+	}
+	if de.KeySource != "(none)" {
+		t.Errorf("KeySource = %q, want (none)", de.KeySource)
+	}
+}
+
+func TestPlainEncPrefixIsNotDecrypted(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{"codec": "enc:utf8"}))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := MustGet[string](cfg, "codec"); got != "enc:utf8" {
+		t.Errorf("codec = %q, want enc:utf8", got)
+	}
+	if cfg.IsSensitive("codec") {
+		t.Error("plain value should not be sensitive")
+	}
+}
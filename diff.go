@@ -0,0 +1,158 @@
+package configo
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Change is one key that differs between two snapshots. Old is nil for an
+// added key and New is nil for a removed one.
+type Change struct {
+	Key string
+	Old any
+	New any
+}
+
+// Diff lists the keys that changed in a reload or override change, each
+// sorted by key.
+type Diff struct {
+	Added    []Change
+	Removed  []Change
+	Modified []Change
+
+	old, new *Snapshot
+}
+
+// Empty reports whether nothing changed.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// Keys returns every changed key in sorted order.
+func (d Diff) Keys() []string {
+	var keys []string
+	for _, group := range [][]Change{d.Added, d.Removed, d.Modified} {
+		for _, ch := range group {
+			keys = append(keys, ch.Key)
+		}
+	}
+	slices.SortFunc(keys, compareKeys)
+	return keys
+}
+
+// Changed reports whether key, or anything nested below it, changed.
+func (d Diff) Changed(key string) bool {
+	for _, group := range [][]Change{d.Added, d.Removed, d.Modified} {
+		for _, ch := range group {
+			if inSubtree(ch.Key, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// under returns the changes at or below prefix. If strip is set, keys are
+// made relative to prefix and the change at prefix itself is dropped.
+func (d Diff) under(prefix string, strip bool) Diff {
+	if prefix == "" {
+		return d
+	}
+	filter := func(changes []Change) []Change {
+		var out []Change
+		for _, ch := range changes {
+			if !inSubtree(ch.Key, prefix) {
+				continue
+			}
+			if strip {
+				rel, ok := strings.CutPrefix(ch.Key, prefix+".")
+				if !ok {
+					continue
+				}
+				ch.Key = rel
+			}
+			out = append(out, ch)
+		}
+		return out
+	}
+	out := Diff{Added: filter(d.Added), Removed: filter(d.Removed), Modified: filter(d.Modified), old: d.old, new: d.new}
+	if strip {
+		out.old, out.new = d.old.Sub(prefix), d.new.Sub(prefix)
+	}
+	return out
+}
+
+// diffSnapshots compares the data of two snapshots.
+func diffSnapshots(old, new *Snapshot) Diff {
+	if old == nil {
+		old = emptySnapshot
+	}
+	if new == nil {
+		new = emptySnapshot
+	}
+	d := Diff{old: old, new: new}
+	for k, nv := range new.data {
+		ov, ok := old.data[k]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, Change{Key: k, New: nv})
+		case !reflect.DeepEqual(ov, nv):
+			d.Modified = append(d.Modified, Change{Key: k, Old: ov, New: nv})
+		}
+	}
+	for k, ov := range old.data {
+		if _, ok := new.data[k]; !ok {
+			d.Removed = append(d.Removed, Change{Key: k, Old: ov})
+		}
+	}
+	for _, group := range [][]Change{d.Added, d.Removed, d.Modified} {
+		slices.SortFunc(group, func(a, b Change) int { return compareKeys(a.Key, b.Key) })
+	}
+	return d
+}
+
+// OnChangeDiff registers a callback that receives the keys that changed
+// when config is reloaded or overridden. It is not called if nothing
+// changed. On a Sub view the diff only holds keys below the view's prefix,
+// relative to it.
+func (c *Config) OnChangeDiff(fn func(Diff)) {
+	if c.root != nil {
+		prefix := c.prefix
+		c.root.OnChangeDiff(func(d Diff) {
+			if sub := d.under(prefix, true); !sub.Empty() {
+				fn(sub)
+			}
+		})
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onDiff = append(c.onDiff, fn)
+}
+
+// OnKeyChange registers a callback that fires only when the value of key
+// changes. For a key with nested values, such as a list or a section, the
+// values are reassembled as by Lookup.
+func (c *Config) OnKeyChange(key string, fn func(Change)) {
+	c.OnChangeDiff(func(d Diff) {
+		if !d.Changed(c.base().normalizer.apply(key)) {
+			return
+		}
+		before, _ := d.old.Lookup(key)
+		after, _ := d.new.Lookup(key)
+		if !reflect.DeepEqual(before, after) {
+			fn(Change{Key: key, Old: before, New: after})
+		}
+	})
+}
+
+// OnPrefixChange registers a callback that fires only when prefix, or a key
+// nested below it, changes. The diff holds just those keys.
+func (c *Config) OnPrefixChange(prefix string, fn func(Diff)) {
+	c.OnChangeDiff(func(d Diff) {
+		if sub := d.under(c.base().normalizer.apply(prefix), false); !sub.Empty() {
+			fn(sub)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package configo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOnChangeDiff(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{
+		"log":      map[string]any{"level": "info"},
+		"database": map[string]any{"host": "db", "port": 5432},
+	}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []Diff
+	cfg.OnChangeDiff(func(d Diff) { got = append(got, d) })
+
+	if err := cfg.Set("log.level", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("cache.ttl", "5m"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Unset("database.port"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("log.level", "debug"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 diffs (last Set changed nothing), got %d", len(got))
+	}
+	if want := []Change{{Key: "log.level", Old: "info", New: "debug"}}; !reflect.DeepEqual(got[0].Modified, want) {
+		t.Errorf("Modified = %v, want %v", got[0].Modified, want)
+	}
+	if want := []Change{{Key: "cache.ttl", New: "5m"}}; !reflect.DeepEqual(got[1].Added, want) {
+		t.Errorf("Added = %v, want %v", got[1].Added, want)
+	}
+	if want := []Change{{Key: "database.port", Old: 5432}}; !reflect.DeepEqual(got[2].Removed, want) {
+		t.Errorf("Removed = %v, want %v", got[2].Removed, want)
+	}
+	if !got[2].Changed("database") || got[2].Changed("log") {
+		t.Error("Changed should match keys at or below the prefix only")
+	}
+}
+
+func TestOnKeyAndPrefixChange(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{
+		"log":      map[string]any{"level": "info"},
+		"database": map[string]any{"host": "db", "port": 5432},
+		"servers":  []any{"a", "b"},
+	}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var levels []Change
+	var dbDiffs, subDiffs []Diff
+	var servers []any
+	cfg.OnKeyChange("log.level", func(ch Change) { levels = append(levels, ch) })
+	cfg.OnPrefixChange("database", func(d Diff) { dbDiffs = append(dbDiffs, d) })
+	cfg.Sub("database").OnChangeDiff(func(d Diff) { subDiffs = append(subDiffs, d) })
+	cfg.OnKeyChange("servers", func(ch Change) { servers = append(servers, ch.New) })
+
+	if err := cfg.Set("log.level", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("database.host", "db2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("servers.1", "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(levels) != 1 || levels[0].Old != "info" || levels[0].New != "debug" {
+		t.Errorf("OnKeyChange(log.level) got %v", levels)
+	}
+	if len(dbDiffs) != 1 || dbDiffs[0].Keys()[0] != "database.host" {
+		t.Errorf("OnPrefixChange(database) got %v", dbDiffs)
+	}
+	if len(subDiffs) != 1 || subDiffs[0].Modified[0].Key != "host" {
+		t.Errorf("Sub OnChangeDiff got %v", subDiffs)
+	}
+	if len(servers) != 1 || !reflect.DeepEqual(servers[0], []any{"a", "c"}) {
+		t.Errorf("OnKeyChange(servers) got %v", servers)
+	}
+}
@@ -47,3 +47,173 @@ func (e *ValidationError) Error() string {
 	}
 	return fmt.Sprintf("validation failed: %s", strings.Join(msgs, "; "))
 }
+
+// UnsupportedFormatError indicates a config file has an extension that no
+// built-in provider can read.
+type UnsupportedFormatError struct {
+	Path string
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	return fmt.Sprintf("unsupported config file format: %s", e.Path)
+}
+
+// InterpolationError indicates a ${...} placeholder could not be resolved.
+// For reference cycles, Cycle holds the keys involved, ending where it began.
+type InterpolationError struct {
+	Key     string
+	Cycle   []string
+	Message string
+}
+
+func (e *InterpolationError) Error() string {
+	if len(e.Cycle) > 0 {
+		return fmt.Sprintf("interpolation cycle: %s", strings.Join(e.Cycle, " -> "))
+	}
+	return fmt.Sprintf("interpolation of %q: %s", e.Key, e.Message)
+}
+
+// SecretError indicates a secret:// reference could not be resolved.
+type SecretError struct {
+	Key    string
+	Scheme string
+	Err    error
+}
+
+func (e *SecretError) Error() string {
+	return fmt.Sprintf("resolve secret for key %q (scheme %q): %v", e.Key, e.Scheme, e.Err)
+}
+
+func (e *SecretError) Unwrap() error {
+	return e.Err
+}
+
+// DecryptionError indicates an enc:v1: value could not be decrypted. KeySource
+// is the key file path or env var, and Provider is where the value came from.
+type DecryptionError struct {
+	Key       string
+	KeySource string
+	Provider  string
+	Err       error
+}
+
+func (e *DecryptionError) Error() string {
+	return fmt.Sprintf("decrypt key %q from %s with key %s: %v", e.Key, e.Provider, e.KeySource, e.Err)
+}
+
+func (e *DecryptionError) Unwrap() error {
+	return e.Err
+}
+
+// ProviderError records the failure of a single provider during Load.
+type ProviderError struct {
+	Provider string
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Provider, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// LoadError lists every provider that failed during a BestEffort or
+// LastKnownGood load. The data from the other providers was still applied.
+type LoadError struct {
+	Errors []ProviderError
+}
+
+func (e *LoadError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, pe := range e.Errors {
+		msgs[i] = pe.Error()
+	}
+	return fmt.Sprintf("load: %d provider(s) failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+func (e *LoadError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i := range e.Errors {
+		errs[i] = &e.Errors[i]
+	}
+	return errs
+}
+
+// KeyCollisionError reports keys from one provider that are spelled
+// differently but normalize to the same key. The last spelling in sorted
+// order wins.
+type KeyCollisionError struct {
+	Key       string
+	Spellings []string
+	Provider  string
+}
+
+func (e *KeyCollisionError) Error() string {
+	return fmt.Sprintf("keys %s from %s all normalize to %q", strings.Join(e.Spellings, ", "), e.Provider, e.Key)
+}
+
+// DeprecatedKeyError reports a value set under a key registered with
+// WithDeprecated.
+type DeprecatedKeyError struct {
+	Key         string
+	Replacement string
+	Message     string
+	Source      Source
+}
+
+func (e *DeprecatedKeyError) Error() string {
+	msg := fmt.Sprintf("config key %q from %s is deprecated, use %q", e.Key, e.Source, e.Replacement)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// AliasConflictError reports an alias and its canonical key that are both
+// set, by one provider or by different layers. The canonical key wins,
+// whichever layer is higher. Source is where the alias was set and
+// KeySource where the canonical key was set.
+type AliasConflictError struct {
+	Alias     string
+	Key       string
+	Source    Source
+	KeySource Source
+}
+
+func (e *AliasConflictError) Error() string {
+	if e.KeySource.Provider == e.Source.Provider {
+		return fmt.Sprintf("config keys %q and %q are both set in %s, using %q", e.Alias, e.Key, e.Source.Provider, e.Key)
+	}
+	return fmt.Sprintf("config key %q from %s and %q from %s are both set, using %q", e.Alias, e.Source, e.Key, e.KeySource, e.Key)
+}
+
+// KeyConflictError reports two paths that claim the same key: the same
+// flattened key produced twice by one provider, or a scalar that also has
+// nested keys. It is returned by Load under WithStrictKeys and passed to the
+// warning handler otherwise.
+type KeyConflictError struct {
+	Key         string
+	Other       string
+	Source      Source
+	OtherSource Source
+}
+
+func (e *KeyConflictError) Error() string {
+	return fmt.Sprintf("config key %s from %s conflicts with %s from %s", e.Key, e.Source, e.Other, e.OtherSource)
+}
+
+// ChangeRejectedError reports new data that failed validation or was vetoed
+// by an OnBeforeChange subscriber. The previous configuration stays active.
+type ChangeRejectedError struct {
+	Err error
+}
+
+func (e *ChangeRejectedError) Error() string {
+	return fmt.Sprintf("config change rejected: %v", e.Err)
+}
+
+func (e *ChangeRejectedError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,165 @@
+package configo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format selects the document format for Export and SaveAs.
+type Format string
+
+const (
+	FormatYAML   Format = "yaml"
+	FormatJSON   Format = "json"
+	FormatTOML   Format = "toml"
+	FormatDotEnv Format = "env"
+)
+
+// FormatForPath returns the format matching the extension of path.
+func FormatForPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	case ".env":
+		return FormatDotEnv, nil
+	}
+	return "", &UnsupportedFormatError{Path: path}
+}
+
+// Export writes the effective configuration to w as a nested document in
+// the given format. Keys are sorted and sensitive values are redacted, so
+// the output is deterministic and safe to keep as a build artifact. Every
+// section in the document holds the value Lookup returns for it.
+//
+// Resolved secret:// values and decrypted enc:v1: values are written as
+// [REDACTED], not as the original reference or ciphertext, so Export is not
+// a lossless converter for files that hold them.
+func (c *Config) Export(w io.Writer, format Format) error {
+	data := c.Redacted()
+	for k, v := range data {
+		data[k] = normalizeNumber(v)
+	}
+	var buf bytes.Buffer
+	switch format {
+	case FormatYAML:
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(Unflatten(data)); err != nil {
+			return fmt.Errorf("export yaml: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("export yaml: %w", err)
+		}
+	case FormatJSON:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(Unflatten(data)); err != nil {
+			return fmt.Errorf("export json: %w", err)
+		}
+	case FormatTOML:
+		if err := toml.NewEncoder(&buf).Encode(Unflatten(data)); err != nil {
+			return fmt.Errorf("export toml: %w", err)
+		}
+	case FormatDotEnv:
+		writeDotEnv(&buf, data)
+	default:
+		return fmt.Errorf("export: unsupported format %q", format)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// SaveAs writes the effective configuration to path, choosing the format
+// from its extension. Sensitive values are redacted as in Export.
+func (c *Config) SaveAs(path string) error {
+	format, err := FormatForPath(path)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := c.Export(&buf, format); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+// normalizeNumber converts whole float64 values, as decoded from JSON, to
+// int64 so that 8080 is written as 8080 and not 8080.0 in every format.
+func normalizeNumber(v any) any {
+	switch val := v.(type) {
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return int64(val)
+		}
+		return val
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = normalizeNumber(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = normalizeNumber(item)
+		}
+		return out
+	}
+	return v
+}
+
+// writeDotEnv writes data as sorted key=value lines with the flat keys
+// unchanged (database.host=db), so provider.DotEnv reads them back as the
+// same keys. Values containing spaces or # are quoted.
+func writeDotEnv(w io.Writer, data map[string]any) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s=%s\n", k, dotEnvValue(data[k]))
+	}
+}
+
+func dotEnvValue(v any) string {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	case float64:
+		s = strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		if items, ok := toAnySlice(v); ok {
+			parts := make([]string, len(items))
+			for i, item := range items {
+				parts[i] = fmt.Sprintf("%v", item)
+			}
+			s = strings.Join(parts, ",")
+		} else {
+			s = fmt.Sprintf("%v", v)
+		}
+	}
+	if !strings.ContainsAny(s, " \t#") {
+		return s
+	}
+	if strings.Contains(s, `"`) {
+		return "'" + s + "'"
+	}
+	return `"` + s + `"`
+}
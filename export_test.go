@@ -0,0 +1,152 @@
+package configo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExportYAMLFromJSON(t *testing.T) {
+	cfg := New(WithFile("testdata/config.json"))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := cfg.Export(&buf, FormatYAML); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `database:
+  host: db.example.com
+  name: myapp
+  port: 5432
+server:
+  debug: true
+  host: localhost
+  port: 8080
+tags:
+  - web
+  - api
+`
+	if buf.String() != want {
+		t.Errorf("Export(yaml) =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestExportDeterministic(t *testing.T) {
+	cfg := New(WithFile("testdata/config.yaml"))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []Format{FormatYAML, FormatJSON, FormatTOML, FormatDotEnv} {
+		var first, second bytes.Buffer
+		if err := cfg.Export(&first, format); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if err := cfg.Export(&second, format); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if first.String() != second.String() {
+			t.Errorf("%s output is not deterministic", format)
+		}
+	}
+}
+
+func TestExportDotEnv(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{
+		"database": map[string]any{"host": "db", "port": float64(5432)},
+		"motd":     "hello world",
+		"tags":     []any{"web", "api"},
+	}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := cfg.Export(&buf, FormatDotEnv); err != nil {
+		t.Fatal(err)
+	}
+	want := "database.host=db\ndatabase.port=5432\nmotd=\"hello world\"\ntags.0=web\ntags.1=api\n"
+	if buf.String() != want {
+		t.Errorf("Export(env) =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestSaveAsRoundTrip(t *testing.T) {
+	cfg := New(WithFile("testdata/config.json"))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"out.yaml", "out.toml", "out.json", "out.env"} {
+		path := filepath.Join(dir, name)
+		if err := cfg.SaveAs(path); err != nil {
+			t.Fatalf("SaveAs(%s): %v", name, err)
+		}
+		reloaded := New(WithFile(path))
+		if err := reloaded.Load(); err != nil {
+			t.Fatalf("reload %s: %v", name, err)
+		}
+		if got := MustGet[int](reloaded, "server.port"); got != 8080 {
+			t.Errorf("%s: server.port = %d, want 8080", name, got)
+		}
+		if got := MustGet[[]string](reloaded, "tags"); !reflect.DeepEqual(got, []string{"web", "api"}) {
+			t.Errorf("%s: tags = %v", name, got)
+		}
+	}
+
+	err := cfg.SaveAs(filepath.Join(dir, "out.ini"))
+	var ufe *UnsupportedFormatError
+	if !errors.As(err, &ufe) {
+		t.Errorf("expected UnsupportedFormatError, got %v", err)
+	}
+}
+
+func TestExportMatchesEffectiveValues(t *testing.T) {
+	t.Setenv("PX_ORIGINS", "https://only.example.com")
+	t.Setenv("PX_DB", "postgres://x")
+	cfg := New(
+		WithDefaults(map[string]any{
+			"origins": []any{"a", "b"},
+			"db":      map[string]any{"host": "h"},
+		}),
+		WithEnvPrefix("PX"),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := cfg.Export(&buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"origins", "db"} {
+		want, _ := cfg.Lookup(key)
+		if !reflect.DeepEqual(doc[key], want) {
+			t.Errorf("exported %s = %v, want effective %v", key, doc[key], want)
+		}
+	}
+	if doc["db"] != "postgres://x" {
+		t.Errorf("exported db = %v, want postgres://x", doc["db"])
+	}
+}
+
+func TestExportRedactsSecrets(t *testing.T) {
+	t.Setenv("CONFIGO_TEST_PASS", "hunter2")
+	cfg := New(WithDefaults(map[string]any{"database.password": "secret://env/CONFIGO_TEST_PASS"}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := cfg.Export(&buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("Export leaked a secret:\n%s", buf.String())
+	}
+}
@@ -2,40 +2,111 @@ package configo
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 // Flatten converts a nested map to a flat map with dot-notation keys.
+// Slices are flattened by index, so servers[1].host becomes "servers.1.host".
+// Empty maps and slices are kept as values. Keys are visited in sorted
+// order, so when a literal "a.b" key and a nested a: {b: ...} both produce
+// "a.b", the literal key deterministically wins.
 func Flatten(m map[string]any) map[string]any {
-	out := make(map[string]any)
-	flatten("", m, out)
-	return out
+	f := newFlattener()
+	f.flatten("", "", m)
+	return f.out
 }
 
-func flatten(prefix string, m map[string]any, out map[string]any) {
-	for k, v := range m {
-		key := k
+// flattener flattens nested maps, recording the key of every list and the
+// original path of every flattened key, so that keys produced twice can be
+// reported.
+type flattener struct {
+	out   map[string]any
+	lists map[string]bool
+	paths map[string]string
+	dups  []flatDup
+}
+
+// flatDup is a flattened key produced by two different paths.
+type flatDup struct {
+	key, first, second string
+}
+
+func newFlattener() *flattener {
+	return &flattener{
+		out:   make(map[string]any),
+		lists: make(map[string]bool),
+		paths: make(map[string]string),
+	}
+}
+
+func (f *flattener) flatten(prefix, path string, m map[string]any) {
+	for _, k := range sortedKeys(m) {
+		key, p := k, pathSegment(k)
 		if prefix != "" {
-			key = prefix + "." + k
+			key, p = prefix+"."+k, path+"."+p
 		}
-		switch val := v.(type) {
-		case map[string]any:
-			if len(val) == 0 {
-				out[key] = val
-			} else {
-				flatten(key, val, out)
-			}
-		case map[any]any:
-			converted := convertMap(val)
-			if len(converted) == 0 {
-				out[key] = converted
-			} else {
-				flatten(key, converted, out)
-			}
-		default:
-			out[key] = v
+		f.value(key, p, m[k])
+	}
+}
+
+func (f *flattener) value(key, path string, v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 0 {
+			f.set(key, path, val)
+		} else {
+			f.flatten(key, path, val)
+		}
+	case map[any]any:
+		converted := convertMap(val)
+		if len(converted) == 0 {
+			f.set(key, path, converted)
+		} else {
+			f.flatten(key, path, converted)
+		}
+	default:
+		items, ok := listItems(v)
+		if !ok {
+			f.set(key, path, v)
+			return
+		}
+		f.lists[key] = true
+		if len(items) == 0 {
+			f.set(key, path, v)
+			return
 		}
+		for i, item := range items {
+			f.value(key+"."+strconv.Itoa(i), path+"."+strconv.Itoa(i), item)
+		}
+	}
+}
+
+func (f *flattener) set(key, path string, v any) {
+	if first, ok := f.paths[key]; ok {
+		f.dups = append(f.dups, flatDup{key: key, first: first, second: path})
 	}
+	f.out[key] = v
+	f.paths[key] = path
+}
+
+// pathSegment quotes a map key that itself contains a dot, so that the
+// literal key "a.b" can be told apart from a nested a.b in messages.
+func pathSegment(k string) string {
+	if strings.Contains(k, ".") {
+		return strconv.Quote(k)
+	}
+	return k
+}
+
+// listItems returns the items of a slice value. Byte slices are treated as
+// single values.
+func listItems(v any) ([]any, bool) {
+	if _, ok := v.([]byte); ok {
+		return nil, false
+	}
+	return toAnySlice(v)
 }
 
 func convertMap(m map[any]any) map[string]any {
@@ -46,11 +117,15 @@ func convertMap(m map[any]any) map[string]any {
 	return out
 }
 
-// Unflatten converts a flat dot-notation map to a nested map.
+// Unflatten converts a flat dot-notation map to a nested map. A map whose
+// keys are exactly 0..n-1 becomes a slice, so "servers.0.host" and
+// "servers[0].host" both rebuild servers as a list. If both a scalar "a"
+// and a nested "a.b" are present, the nested keys deterministically win.
 func Unflatten(m map[string]any) map[string]any {
 	out := make(map[string]any)
-	for k, v := range m {
-		parts := strings.Split(k, ".")
+	for _, k := range sortedKeys(m) {
+		v := m[k]
+		parts := strings.Split(indexPath(k), ".")
 		current := out
 		for i, part := range parts {
 			if i == len(parts)-1 {
@@ -72,5 +147,96 @@ func Unflatten(m map[string]any) map[string]any {
 			}
 		}
 	}
+	for k, v := range out {
+		out[k] = listify(v)
+	}
+	return out
+}
+
+// listify converts nested maps with dense numeric keys into slices.
+func listify(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) == 0 {
+		return v
+	}
+	for k, item := range m {
+		m[k] = listify(item)
+	}
+	items := make([]any, len(m))
+	for k, item := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(items) || strconv.Itoa(i) != k {
+			return m
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// indexPath rewrites bracket indexes to dot notation: "servers[0].host"
+// becomes "servers.0.host".
+func indexPath(key string) string {
+	if !strings.Contains(key, "[") {
+		return key
+	}
+	r := strings.NewReplacer("[", ".", "]", "")
+	return strings.TrimPrefix(r.Replace(key), ".")
+}
+
+// subtree returns the entries of flat nested below prefix, with keys
+// relative to it.
+func subtree(flat map[string]any, prefix string) map[string]any {
+	var out map[string]any
+	for k, v := range flat {
+		if rel, ok := strings.CutPrefix(k, prefix+"."); ok {
+			if out == nil {
+				out = make(map[string]any)
+			}
+			out[rel] = v
+		}
+	}
+	return out
+}
+
+// parentKeys returns the set of keys that have nested keys below them in
+// flat: "servers" and "servers.0" for "servers.0.host".
+func parentKeys(flat map[string]any) map[string]bool {
+	out := make(map[string]bool)
+	for k := range flat {
+		for p := range parents(k) {
+			out[p] = true
+		}
+	}
+	return out
+}
+
+// assemble rebuilds the nested value stored below prefix in flat, as a map
+// or, for list entries, a slice.
+func assemble(flat map[string]any, prefix string) (any, bool) {
+	sub := subtree(flat, prefix)
+	if sub == nil {
+		return nil, false
+	}
+	return listify(Unflatten(sub)), true
+}
+
+// listIndex reports whether the first segment of rel is a list index, and
+// returns it and the rest of the key.
+func listIndex(rel string) (int, string, bool) {
+	head, rest, _ := strings.Cut(rel, ".")
+	i, err := strconv.Atoi(head)
+	if err != nil || i < 0 || strconv.Itoa(i) != head {
+		return 0, "", false
+	}
+	return i, rest, true
+}
+
+// sortedIndexes returns the indexes of items in ascending order.
+func sortedIndexes[V any](items map[int]V) []int {
+	out := make([]int, 0, len(items))
+	for i := range items {
+		out = append(out, i)
+	}
+	sort.Ints(out)
 	return out
 }
@@ -63,13 +63,35 @@ func TestFlatten(t *testing.T) {
 			},
 		},
 		{
-			name: "array values preserved",
+			name: "slices flattened by index",
 			in: map[string]any{
 				"tags": []string{"a", "b"},
+				"servers": []any{
+					map[string]any{"host": "a"},
+					map[string]any{"host": "b", "ports": []any{80, 443}},
+				},
 			},
 			want: map[string]any{
-				"tags": []string{"a", "b"},
+				"tags.0":            "a",
+				"tags.1":            "b",
+				"servers.0.host":    "a",
+				"servers.1.host":    "b",
+				"servers.1.ports.0": 80,
+				"servers.1.ports.1": 443,
+			},
+		},
+		{
+			name: "literal dotted key wins over nested",
+			in: map[string]any{
+				"a.b": 1,
+				"a":   map[string]any{"b": 2},
 			},
+			want: map[string]any{"a.b": 1},
+		},
+		{
+			name: "empty slice kept",
+			in:   map[string]any{"tags": []any{}},
+			want: map[string]any{"tags": []any{}},
 		},
 	}
 
@@ -118,6 +140,22 @@ func TestUnflatten(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "indexed keys to slices",
+			in: map[string]any{
+				"servers.0.host":    "a",
+				"servers[1].host":   "b",
+				"servers.1.ports.0": 80,
+				"codes.1":           "sparse",
+			},
+			want: map[string]any{
+				"servers": []any{
+					map[string]any{"host": "a"},
+					map[string]any{"host": "b", "ports": []any{80}},
+				},
+				"codes": map[string]any{"1": "sparse"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -130,6 +168,16 @@ func TestUnflatten(t *testing.T) {
 	}
 }
 
+func TestUnflattenNestedWinsOverScalar(t *testing.T) {
+	for range 20 {
+		got := Unflatten(map[string]any{"log": "debug", "log.level": "info"})
+		want := map[string]any{"log": map[string]any{"level": "info"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Unflatten() = %v, want %v", got, want)
+		}
+	}
+}
+
 func TestFlattenUnflattenRoundTrip(t *testing.T) {
 	original := map[string]any{
 		"database": map[string]any{
@@ -139,6 +187,10 @@ func TestFlattenUnflattenRoundTrip(t *testing.T) {
 		"server": map[string]any{
 			"name": "app",
 		},
+		"servers": []any{
+			map[string]any{"host": "a"},
+			map[string]any{"host": "b"},
+		},
 	}
 
 	flat := Flatten(original)
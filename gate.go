@@ -0,0 +1,89 @@
+package configo
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// WithValidation checks every candidate configuration against rules before
+// it is published, on Load, on Watch reloads and on override changes. A
+// candidate that fails is rejected with a ChangeRejectedError and the
+// previous configuration stays active.
+func WithValidation(rules map[string]Rule) Option {
+	return func(c *Config) {
+		c.validators = append(c.validators, func(next *Snapshot) error {
+			return next.Validate(rules)
+		})
+	}
+}
+
+// WithValidateStruct is like WithValidation, with rules taken from the
+// `validate` tags of target, a struct or pointer to struct. If *T has a
+// Validate() error method, each candidate is also bound into a new T and
+// Validate is called on it.
+func WithValidateStruct(target any) Option {
+	return func(c *Config) {
+		t := reflect.TypeOf(target)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			c.optErrs = append(c.optErrs, fmt.Errorf("validate: target must be a struct"))
+			return
+		}
+		rules := make(map[string]Rule)
+		buildRulesFromStruct(t, rules)
+		c.validators = append(c.validators, func(next *Snapshot) error {
+			if err := next.Validate(rules); err != nil {
+				return err
+			}
+			v := reflect.New(t)
+			validator, ok := v.Interface().(interface{ Validate() error })
+			if !ok {
+				return nil
+			}
+			if err := next.Bind(v.Interface()); err != nil {
+				return err
+			}
+			return validator.Validate()
+		})
+	}
+}
+
+// OnBeforeChange registers a callback that can veto new data before it is
+// published by returning an error. It runs after validation, with the
+// configuration still unchanged; a rejected change is returned from Load,
+// Set, Unset or ClearOverrides as a ChangeRejectedError. The callback must
+// not call Load or change overrides itself.
+func (c *Config) OnBeforeChange(fn func(next *Snapshot) error) {
+	if c.root != nil {
+		prefix := c.prefix
+		c.root.OnBeforeChange(func(next *Snapshot) error {
+			return fn(next.Sub(prefix))
+		})
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.beforeChange = append(c.beforeChange, fn)
+}
+
+// check runs validation and every OnBeforeChange subscriber against a
+// candidate snapshot.
+func (c *Config) check(next *Snapshot) error {
+	for _, validate := range c.validators {
+		if err := validate(next); err != nil {
+			return &ChangeRejectedError{Err: err}
+		}
+	}
+	c.mu.RLock()
+	handlers := slices.Clone(c.beforeChange)
+	c.mu.RUnlock()
+	for _, fn := range handlers {
+		if err := fn(next); err != nil {
+			return &ChangeRejectedError{Err: err}
+		}
+	}
+	return nil
+}
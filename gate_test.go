@@ -0,0 +1,115 @@
+package configo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidationGateKeepsPreviousConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  port: 8080\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	minPort := 1.0
+	cfg := New(
+		WithFile(path),
+		WithValidation(map[string]Rule{"server.port": {Required: true, Min: &minPort}}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	version := cfg.Snapshot().Version()
+
+	// A half-saved file that drops the required key.
+	if err := os.WriteFile(path, []byte("server:\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := cfg.Load()
+	var re *ChangeRejectedError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected ChangeRejectedError, got %v", err)
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Errorf("rejection should wrap the ValidationError, got %v", re.Err)
+	}
+	if got := MustGet[int](cfg, "server.port"); got != 8080 {
+		t.Errorf("server.port = %d, want previous 8080", got)
+	}
+	if cfg.Snapshot().Version() != version {
+		t.Error("a rejected reload should not publish a new version")
+	}
+
+	if err := cfg.Set("server.port", 0); !errors.As(err, &re) {
+		t.Errorf("expected override to be rejected, got %v", err)
+	}
+	if len(cfg.Overrides()) != 0 {
+		t.Error("rejected override should be rolled back")
+	}
+}
+
+type gatedServer struct {
+	Host string `config:"server.host" validate:"required"`
+	Port int    `config:"server.port"`
+}
+
+func (s *gatedServer) Validate() error {
+	if s.Port == 443 && s.Host == "localhost" {
+		return fmt.Errorf("port 443 needs a public host")
+	}
+	return nil
+}
+
+func TestValidateStructGate(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"server": map[string]any{"host": "localhost", "port": 80}}),
+		WithValidateStruct(&gatedServer{}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("server.port", 443); err == nil {
+		t.Error("struct Validate method should reject the change")
+	}
+	if err := cfg.Unset("server.host"); err == nil {
+		t.Error("validate tags should reject the change")
+	}
+	if got := MustGet[int](cfg, "server.port"); got != 80 {
+		t.Errorf("server.port = %d, want 80", got)
+	}
+}
+
+func TestOnBeforeChangeVeto(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{"database": map[string]any{"host": "db1"}}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var notified int
+	cfg.OnChange(func(*Config) { notified++ })
+	errVeto := errors.New("host not allowed")
+	cfg.Sub("database").OnBeforeChange(func(next *Snapshot) error {
+		if MustGet[string](next, "host") == "forbidden" {
+			return errVeto
+		}
+		return nil
+	})
+
+	if err := cfg.Set("database.host", "forbidden"); !errors.Is(err, errVeto) {
+		t.Fatalf("expected veto, got %v", err)
+	}
+	if got := MustGet[string](cfg, "database.host"); got != "db1" {
+		t.Errorf("database.host = %q, want db1", got)
+	}
+	if err := cfg.Set("database.host", "db2"); err != nil {
+		t.Fatal(err)
+	}
+	if notified != 1 {
+		t.Errorf("OnChange fired %d times, want only for the accepted change", notified)
+	}
+}
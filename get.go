@@ -6,11 +6,9 @@ import (
 	"time"
 )
 
-// Get retrieves a typed value from the config.
-func Get[T any](c *Config, key string) (T, error) {
-	c.mu.RLock()
-	val, ok := c.data[key]
-	c.mu.RUnlock()
+// Get retrieves a typed value from a Config or Snapshot.
+func Get[T any](c Getter, key string) (T, error) {
+	val, ok := c.Lookup(key)
 
 	var zero T
 	if !ok {
@@ -25,7 +23,7 @@ func Get[T any](c *Config, key string) (T, error) {
 }
 
 // GetOr retrieves a typed value, returning fallback if the key is missing.
-func GetOr[T any](c *Config, key string, fallback T) T {
+func GetOr[T any](c Getter, key string, fallback T) T {
 	val, err := Get[T](c, key)
 	if err != nil {
 		return fallback
@@ -34,7 +32,7 @@ func GetOr[T any](c *Config, key string, fallback T) T {
 }
 
 // MustGet retrieves a typed value and panics if the key is missing or conversion fails.
-func MustGet[T any](c *Config, key string) T {
+func MustGet[T any](c Getter, key string) T {
 	val, err := Get[T](c, key)
 	if err != nil {
 		panic(fmt.Sprintf("configo: %v", err))
@@ -6,7 +6,9 @@ import (
 )
 
 func newTestConfig(data map[string]any) *Config {
-	c := &Config{data: Flatten(data)}
+	c := &Config{}
+	flat := Flatten(data)
+	c.snap.Store(&Snapshot{data: flat, parents: parentKeys(flat)})
 	return c
 }
 
@@ -0,0 +1,151 @@
+package configo
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// WithoutInterpolation disables ${...} placeholder resolution in Load.
+func WithoutInterpolation() Option {
+	return func(c *Config) {
+		c.noInterpolation = true
+	}
+}
+
+// interpolate resolves placeholders in every string value of data:
+//
+//	${database.host}        value of another key
+//	${env:HOME}             environment variable
+//	${database.port:-5432}  fallback when the key or variable is unset
+//	$${                     a literal ${
+//
+// A value that is a single placeholder keeps the referenced value's type.
+// Keys already marked in sensitive are left as-is, and any value built from
+// a sensitive key is marked sensitive too.
+func interpolate(data map[string]any, sensitive map[string]bool, normalize KeyNormalizer) (map[string]any, error) {
+	r := &interpolator{
+		data:      data,
+		sensitive: sensitive,
+		normalize: normalize,
+		resolved:  make(map[string]any, len(data)),
+		active:    make(map[string]bool),
+	}
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, err := r.resolve(k); err != nil {
+			return nil, err
+		}
+	}
+	return r.resolved, nil
+}
+
+type interpolator struct {
+	data      map[string]any
+	sensitive map[string]bool
+	normalize KeyNormalizer
+	resolved  map[string]any
+	active    map[string]bool
+	stack     []string
+}
+
+func (r *interpolator) resolve(key string) (any, error) {
+	if v, ok := r.resolved[key]; ok {
+		return v, nil
+	}
+	if r.active[key] {
+		start := 0
+		for i, k := range r.stack {
+			if k == key {
+				start = i
+				break
+			}
+		}
+		cycle := append(append([]string(nil), r.stack[start:]...), key)
+		return nil, &InterpolationError{Key: r.stack[0], Cycle: cycle}
+	}
+
+	v := r.data[key]
+	s, ok := v.(string)
+	if !ok || r.sensitive[key] || !strings.Contains(s, "${") {
+		r.resolved[key] = v
+		return v, nil
+	}
+
+	r.active[key] = true
+	r.stack = append(r.stack, key)
+	out, err := r.expand(key, s)
+	r.stack = r.stack[:len(r.stack)-1]
+	delete(r.active, key)
+	if err != nil {
+		return nil, err
+	}
+	r.resolved[key] = out
+	return out, nil
+}
+
+// expand substitutes every placeholder in s, which is the value of key.
+func (r *interpolator) expand(key, s string) (any, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1])
+			b.WriteString("${")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return nil, &InterpolationError{Key: key, Message: fmt.Sprintf("unterminated placeholder in %q", s)}
+		}
+		val, err := r.lookup(key, s[i+2:i+end])
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 && end == len(s)-1 && b.Len() == 0 {
+			return val, nil
+		}
+		b.WriteString(s[:i])
+		fmt.Fprintf(&b, "%v", val)
+		s = s[i+end+1:]
+	}
+	return b.String(), nil
+}
+
+func (r *interpolator) lookup(key, ref string) (any, error) {
+	ref, fallback, hasFallback := strings.Cut(ref, ":-")
+	ref = strings.TrimSpace(ref)
+
+	if name, ok := strings.CutPrefix(ref, "env:"); ok {
+		if v := os.Getenv(name); v != "" {
+			return v, nil
+		}
+		if hasFallback {
+			return fallback, nil
+		}
+		return nil, &InterpolationError{Key: key, Message: fmt.Sprintf("environment variable %s is not set", name)}
+	}
+
+	ref = r.normalize.apply(ref)
+	if _, ok := r.data[ref]; !ok {
+		if hasFallback {
+			return fallback, nil
+		}
+		return nil, &InterpolationError{Key: key, Message: fmt.Sprintf("unresolved reference ${%s}", ref)}
+	}
+	v, err := r.resolve(ref)
+	if err == nil && r.sensitive[ref] {
+		r.sensitive[key] = true
+	}
+	return v, err
+}
@@ -0,0 +1,90 @@
+package configo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("CONFIGO_TEST_USER", "admin")
+
+	got, err := interpolate(map[string]any{
+		"database.host":  "db.local",
+		"database.port":  5432,
+		"database.user":  "${env:CONFIGO_TEST_USER}",
+		"database.dsn":   "postgres://${database.user}@${database.host}:${database.port}/app",
+		"database.pool":  "${database.pool_size:-10}",
+		"database.alias": "${database.port}",
+		"home":           "${env:CONFIGO_TEST_UNSET:-/tmp}",
+		"literal":        "$${not.a.ref}",
+	}, map[string]bool{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]any{
+		"database.host":  "db.local",
+		"database.port":  5432,
+		"database.user":  "admin",
+		"database.dsn":   "postgres://admin@db.local:5432/app",
+		"database.pool":  "10",
+		"database.alias": 5432,
+		"home":           "/tmp",
+		"literal":        "${not.a.ref}",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("interpolate() = %v, want %v", got, want)
+	}
+}
+
+func TestInterpolateCycle(t *testing.T) {
+	_, err := interpolate(map[string]any{
+		"a": "${b}",
+		"b": "x-${c}",
+		"c": "${a}",
+	}, map[string]bool{}, nil)
+	var ie *InterpolationError
+	if !errors.As(err, &ie) {
+		t.Fatalf("expected InterpolationError, got %v", err)
+	}
+	want := []string{"a", "b", "c", "a"}
+	if !reflect.DeepEqual(ie.Cycle, want) {
+		t.Errorf("Cycle = %v, want %v", ie.Cycle, want)
+	}
+}
+
+func TestInterpolateUnresolved(t *testing.T) {
+	_, err := interpolate(map[string]any{"dsn": "postgres://${database.host}/app"}, map[string]bool{}, nil)
+	var ie *InterpolationError
+	if !errors.As(err, &ie) {
+		t.Fatalf("expected InterpolationError, got %v", err)
+	}
+	if ie.Key != "dsn" {
+		t.Errorf("Key = %q, want dsn", ie.Key)
+	}
+}
+
+func TestConfigLoadInterpolates(t *testing.T) {
+	cfg := New(
+		WithFile("testdata/config.yaml"),
+		WithDefaults(map[string]any{"database.dsn": "postgres://${database.host}:${database.port}/${database.name}"}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := MustGet[string](cfg, "database.dsn"); got != "postgres://db.example.com:5432/myapp" {
+		t.Errorf("database.dsn = %q", got)
+	}
+
+	cfg = New(
+		WithDefaults(map[string]any{"template": "${name}"}),
+		WithoutInterpolation(),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Data()["template"]; got != "${name}" {
+		t.Errorf("template = %v, want raw placeholder", got)
+	}
+}
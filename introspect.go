@@ -0,0 +1,129 @@
+package configo
+
+import (
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Keys returns every key in sorted order. List indexes sort numerically.
+func (c *Config) Keys() []string {
+	return c.Snapshot().Keys()
+}
+
+// Keys returns every key in the snapshot in sorted order.
+func (s *Snapshot) Keys() []string {
+	var keys []string
+	s.each(func(k string, _ any, _ bool) {
+		keys = append(keys, k)
+	})
+	slices.SortFunc(keys, compareKeys)
+	return keys
+}
+
+// IsSet reports whether key has a value, either directly or nested below it.
+func (c *Config) IsSet(key string) bool {
+	return c.Snapshot().IsSet(key)
+}
+
+// IsSet reports whether key has a value in the snapshot.
+func (s *Snapshot) IsSet(key string) bool {
+	_, ok := s.Lookup(key)
+	return ok
+}
+
+// KeysUnder returns the names of the direct children of prefix, sorted, so
+// that KeysUnder("services") returns ["api", "web"] for services.api.* and
+// services.web.*. An empty prefix returns the top-level names.
+func (c *Config) KeysUnder(prefix string) []string {
+	return c.Snapshot().KeysUnder(prefix)
+}
+
+// KeysUnder returns the names of the direct children of prefix in the
+// snapshot.
+func (s *Snapshot) KeysUnder(prefix string) []string {
+	scoped := s.Sub(prefix)
+	seen := make(map[string]bool)
+	var names []string
+	scoped.each(func(k string, _ any, _ bool) {
+		name, _, _ := strings.Cut(k, ".")
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	})
+	slices.SortFunc(names, compareKeys)
+	return names
+}
+
+// Match returns the keys that match pattern, in sorted order. A "*" segment
+// matches any single segment, so "services.*.port" matches services.api.port
+// and "services.*" matches services.api even though it has nested keys.
+func (c *Config) Match(pattern string) []string {
+	return c.Snapshot().Match(pattern)
+}
+
+// Match returns the keys in the snapshot that match pattern.
+func (s *Snapshot) Match(pattern string) []string {
+	want := strings.Split(s.normalize.apply(pattern), ".")
+	seen := make(map[string]bool)
+	var keys []string
+	s.each(func(k string, _ any, _ bool) {
+		parts := strings.Split(k, ".")
+		if len(parts) < len(want) {
+			return
+		}
+		key := strings.Join(parts[:len(want)], ".")
+		if !seen[key] && matchSegments(want, parts[:len(want)]) {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	})
+	slices.SortFunc(keys, compareKeys)
+	return keys
+}
+
+// All returns an iterator over every key and its value, in sorted order.
+// The values come from a single snapshot.
+func (c *Config) All() iter.Seq2[string, any] {
+	return c.Snapshot().All()
+}
+
+// All returns an iterator over the snapshot's keys and values.
+func (s *Snapshot) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, k := range s.Keys() {
+			if !yield(k, s.data[s.fullKey(k)]) {
+				return
+			}
+		}
+	}
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for i, p := range pattern {
+		if p != "*" && p != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// compareKeys orders dot-notation keys segment by segment, comparing list
+// indexes numerically so that servers.2 sorts before servers.10.
+func compareKeys(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range min(len(as), len(bs)) {
+		if as[i] == bs[i] {
+			continue
+		}
+		an, aerr := strconv.Atoi(as[i])
+		bn, berr := strconv.Atoi(bs[i])
+		if aerr == nil && berr == nil {
+			return an - bn
+		}
+		return strings.Compare(as[i], bs[i])
+	}
+	return len(as) - len(bs)
+}
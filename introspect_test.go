@@ -0,0 +1,97 @@
+package configo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newServicesConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg := New(WithDefaults(map[string]any{
+		"services": map[string]any{
+			"web": map[string]any{"port": 80, "host": "web.internal"},
+			"api": map[string]any{"port": 8080},
+		},
+		"servers": []any{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+		"debug":   true,
+	}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestKeys(t *testing.T) {
+	cfg := newServicesConfig(t)
+	keys := cfg.Keys()
+	if len(keys) != 15 {
+		t.Fatalf("expected 15 keys, got %v", keys)
+	}
+	if keys[0] != "debug" || keys[1] != "servers.0" || keys[3] != "servers.2" || keys[11] != "servers.10" {
+		t.Errorf("keys not in sorted order: %v", keys)
+	}
+
+	want := []string{"host", "port"}
+	if got := cfg.Sub("services.web").Keys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sub keys = %v, want %v", got, want)
+	}
+}
+
+func TestIsSet(t *testing.T) {
+	cfg := newServicesConfig(t)
+	for key, want := range map[string]bool{
+		"debug":             true,
+		"services.web.port": true,
+		"services":          true,
+		"servers[3]":        true,
+		"services.db":       false,
+		"missing":           false,
+	} {
+		if got := cfg.IsSet(key); got != want {
+			t.Errorf("IsSet(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestKeysUnder(t *testing.T) {
+	cfg := newServicesConfig(t)
+	if got := cfg.KeysUnder("services"); !reflect.DeepEqual(got, []string{"api", "web"}) {
+		t.Errorf("KeysUnder(services) = %v, want [api web]", got)
+	}
+	if got := cfg.KeysUnder(""); !reflect.DeepEqual(got, []string{"debug", "servers", "services"}) {
+		t.Errorf("KeysUnder(\"\") = %v", got)
+	}
+	if got := cfg.KeysUnder("debug"); len(got) != 0 {
+		t.Errorf("KeysUnder(debug) = %v, want none", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	cfg := newServicesConfig(t)
+	if got := cfg.Match("services.*.port"); !reflect.DeepEqual(got, []string{"services.api.port", "services.web.port"}) {
+		t.Errorf("Match(services.*.port) = %v", got)
+	}
+	if got := cfg.Match("services.*"); !reflect.DeepEqual(got, []string{"services.api", "services.web"}) {
+		t.Errorf("Match(services.*) = %v", got)
+	}
+	if got := cfg.Match("*.*.host"); !reflect.DeepEqual(got, []string{"services.web.host"}) {
+		t.Errorf("Match(*.*.host) = %v", got)
+	}
+}
+
+func TestAll(t *testing.T) {
+	cfg := newServicesConfig(t)
+	var keys []string
+	for k, v := range cfg.Sub("services").All() {
+		keys = append(keys, k)
+		if k == "api.port" && v != 8080 {
+			t.Errorf("api.port = %v, want 8080", v)
+		}
+		if len(keys) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(keys, []string{"api.port", "web.host"}) {
+		t.Errorf("All() keys = %v", keys)
+	}
+}
@@ -0,0 +1,147 @@
+package configo
+
+import (
+	"sort"
+	"strings"
+)
+
+// KeyNormalizer maps a dot-notation key to its canonical form. Keys from
+// every provider and every key passed to a lookup are normalized, so that
+// Server.Port in YAML and APP_SERVER_PORT in the environment are the same key.
+type KeyNormalizer func(key string) string
+
+// LowercaseKeys is the default KeyNormalizer.
+func LowercaseKeys(key string) string {
+	return strings.ToLower(key)
+}
+
+// WithKeyNormalizer replaces the default lowercase key normalization. A nil
+// normalizer makes keys case-sensitive.
+func WithKeyNormalizer(fn KeyNormalizer) Option {
+	return func(c *Config) {
+		c.normalizer = fn
+	}
+}
+
+// apply normalizes key, after rewriting any bracket indexes to dot
+// notation.
+func (n KeyNormalizer) apply(key string) string {
+	key = indexPath(key)
+	if n == nil {
+		return key
+	}
+	return n(key)
+}
+
+// WithStrictKeys makes Load fail with a KeyConflictError when two paths in
+// one layer claim the same key, such as a literal "a.b" key next to
+// a: {b: ...}, or a scalar next to a nested key, as APP_LOG and
+// APP_LOG_LEVEL do. Without it, conflicts are passed to the warning handler.
+// A higher layer never conflicts with a lower one: it replaces the keys at,
+// below and above the keys it sets.
+func WithStrictKeys() Option {
+	return func(c *Config) {
+		c.strictKeys = true
+	}
+}
+
+// keyConflict fails in strict mode and warns otherwise.
+func (c *Config) keyConflict(err *KeyConflictError) error {
+	if c.strictKeys {
+		return err
+	}
+	c.warn(err)
+	return nil
+}
+
+// flattenLayer flattens one provider's data and normalizes its keys,
+// reporting duplicate paths as key conflicts and differently spelled keys
+// through the warning handler. It also returns the keys of the layer's
+// lists.
+func (c *Config) flattenLayer(m map[string]any, source func(string, any) Source) (map[string]any, map[string]bool, error) {
+	f := newFlattener()
+	f.flatten("", "", m)
+	for _, d := range f.dups {
+		src := source(d.key, f.out[d.key])
+		if err := c.keyConflict(&KeyConflictError{Key: d.first, Other: d.second, Source: src, OtherSource: src}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	lists := make(map[string]bool, len(f.lists))
+	for k := range f.lists {
+		lists[c.normalizer.apply(k)] = true
+	}
+
+	out := make(map[string]any, len(f.out))
+	spellings := make(map[string][]string)
+	for _, k := range sortedKeys(f.out) {
+		nk := c.normalizer.apply(k)
+		out[nk] = f.out[k]
+		spellings[nk] = append(spellings[nk], k)
+	}
+	for _, nk := range sortedKeys(spellings) {
+		if s := spellings[nk]; len(s) > 1 {
+			c.warn(&KeyCollisionError{Key: nk, Spellings: s, Provider: source(nk, nil).Provider})
+		}
+	}
+	return out, lists, nil
+}
+
+// checkConflicts reports every merged scalar that also has nested keys,
+// such as log=debug next to log.level=info. Since mergeLayer shadows lower
+// layers, these come from a single layer. An empty map or list that has
+// been filled in by a higher layer is dropped instead.
+func (c *Config) checkConflicts(merged map[string]any, sources map[string][]Source) error {
+	reported := make(map[string]bool)
+	for _, k := range sortedKeys(merged) {
+		for i := range len(k) {
+			if k[i] != '.' {
+				continue
+			}
+			parent := k[:i]
+			v, ok := merged[parent]
+			if !ok || reported[parent] {
+				continue
+			}
+			if isEmptyContainer(v) {
+				delete(merged, parent)
+				delete(sources, parent)
+				continue
+			}
+			reported[parent] = true
+			err := &KeyConflictError{Key: parent, Other: k, Source: lastSource(sources[parent]), OtherSource: lastSource(sources[k])}
+			if err := c.keyConflict(err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isEmptyContainer(v any) bool {
+	switch val := v.(type) {
+	case map[string]any:
+		return len(val) == 0
+	case map[any]any:
+		return len(val) == 0
+	}
+	items, ok := listItems(v)
+	return ok && len(items) == 0
+}
+
+func lastSource(layers []Source) Source {
+	if len(layers) == 0 {
+		return Source{}
+	}
+	return layers[len(layers)-1]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
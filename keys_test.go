@@ -0,0 +1,231 @@
+package configo
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/devaloi/configo/provider"
+)
+
+func TestKeysLowercasedByDefault(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"Server": map[string]any{"Port": 8080}}),
+		WithProvider(provider.NewDefaults(map[string]any{"server": map[string]any{"HOST": "example.com"}})),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := MustGet[int](cfg, "server.port"); got != 8080 {
+		t.Errorf("server.port = %d, want 8080", got)
+	}
+	if got := MustGet[string](cfg, "SERVER.Host"); got != "example.com" {
+		t.Errorf("SERVER.Host = %q, want example.com", got)
+	}
+	if got := MustGet[int](cfg.Sub("Server"), "PORT"); got != 8080 {
+		t.Errorf("Sub(Server).PORT = %d, want 8080", got)
+	}
+	if _, ok := cfg.Data()["Server.Port"]; ok {
+		t.Error("Data should only contain normalized keys")
+	}
+}
+
+func TestKeyNormalizerAppliesToBindAndOverrides(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{"Database": map[string]any{"Host": "localhost"}}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("DATABASE.HOST", "db.internal"); err != nil {
+		t.Fatal(err)
+	}
+
+	var dst struct {
+		Host string `config:"Database.Host"`
+	}
+	if err := cfg.Bind(&dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Host != "db.internal" {
+		t.Errorf("Host = %q, want db.internal", dst.Host)
+	}
+}
+
+func TestCaseSensitiveKeys(t *testing.T) {
+	cfg := New(
+		WithKeyNormalizer(nil),
+		WithDefaults(map[string]any{"Port": 1, "port": 2}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := MustGet[int](cfg, "Port"); got != 1 {
+		t.Errorf("Port = %d, want 1", got)
+	}
+	if got := MustGet[int](cfg, "port"); got != 2 {
+		t.Errorf("port = %d, want 2", got)
+	}
+}
+
+func TestCustomKeyNormalizer(t *testing.T) {
+	cfg := New(
+		WithKeyNormalizer(func(k string) string {
+			return strings.ReplaceAll(strings.ToLower(k), "-", "_")
+		}),
+		WithDefaults(map[string]any{"max-conns": 10, "url": "${MAX-CONNS}"}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := MustGet[int](cfg, "max_conns"); got != 10 {
+		t.Errorf("max_conns = %d, want 10", got)
+	}
+	if got := MustGet[int](cfg, "url"); got != 10 {
+		t.Errorf("url = %d, want 10", got)
+	}
+}
+
+func TestKeyCollisionReported(t *testing.T) {
+	var warnings []error
+	cfg := New(
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }),
+		WithDefaults(map[string]any{"Port": 1, "port": 2}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", warnings)
+	}
+	var ke *KeyCollisionError
+	if !errors.As(warnings[0], &ke) {
+		t.Fatalf("expected KeyCollisionError, got %T", warnings[0])
+	}
+	if ke.Key != "port" || len(ke.Spellings) != 2 || ke.Provider != "defaults" {
+		t.Errorf("unexpected collision %+v", ke)
+	}
+	// Sorted order: "Port" < "port", so the lowercase spelling wins.
+	if got := MustGet[int](cfg, "port"); got != 2 {
+		t.Errorf("port = %d, want 2", got)
+	}
+}
+
+func TestKeyConflictLiteralAndNested(t *testing.T) {
+	data := map[string]any{
+		"a.b": 1,
+		"a":   map[string]any{"b": 2},
+	}
+
+	var warnings []error
+	cfg := New(
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }),
+		WithDefaults(data),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := MustGet[int](cfg, "a.b"); got != 1 {
+		t.Errorf("a.b = %d, want the literal key's 1", got)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", warnings)
+	}
+
+	err := New(WithStrictKeys(), WithDefaults(data)).Load()
+	var ke *KeyConflictError
+	if !errors.As(err, &ke) {
+		t.Fatalf("expected KeyConflictError, got %v", err)
+	}
+	if ke.Key != "a.b" || ke.Other != `"a.b"` || ke.Source.Provider != "defaults" {
+		t.Errorf("unexpected conflict %+v", ke)
+	}
+}
+
+func TestKeyConflictScalarAndNested(t *testing.T) {
+	t.Setenv("APP_LOG", "debug")
+	t.Setenv("APP_LOG_LEVEL", "info")
+
+	err := New(WithStrictKeys(), WithEnvPrefix("APP")).Load()
+	var ke *KeyConflictError
+	if !errors.As(err, &ke) {
+		t.Fatalf("expected KeyConflictError, got %v", err)
+	}
+	if ke.Key != "log" || ke.Other != "log.level" {
+		t.Errorf("unexpected conflict %+v", ke)
+	}
+	if ke.Source.Location != "APP_LOG" || ke.OtherSource.Location != "APP_LOG_LEVEL" {
+		t.Errorf("conflict should name both env vars, got %v", ke)
+	}
+
+	var warnings []error
+	cfg := New(
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }),
+		WithEnvPrefix("APP"),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected 1 warning in lenient mode, got %v", warnings)
+	}
+	got, ok := cfg.Lookup("log")
+	want := Unflatten(cfg.Data())["log"]
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("Lookup(log) = %v, want %v as in Unflatten", got, want)
+	}
+}
+
+func TestHigherLayerScalarReplacesSubtree(t *testing.T) {
+	t.Setenv("PX_ORIGINS", "https://only.example.com")
+	t.Setenv("PX_DB_HOST", "h")
+
+	for _, strict := range []bool{false, true} {
+		var warnings []error
+		opts := []Option{
+			WithWarningHandler(func(err error) { warnings = append(warnings, err) }),
+			WithDefaults(map[string]any{
+				"origins": []any{"a", "b"},
+				"db":      "postgres://x",
+			}),
+			WithEnvPrefix("PX"),
+		}
+		if strict {
+			opts = append(opts, WithStrictKeys())
+		}
+		cfg := New(opts...)
+		if err := cfg.Load(); err != nil {
+			t.Fatalf("strict=%v: unexpected error: %v", strict, err)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("strict=%v: unexpected warnings %v", strict, warnings)
+		}
+		if got := MustGet[string](cfg, "origins"); got != "https://only.example.com" {
+			t.Errorf("strict=%v: origins = %q", strict, got)
+		}
+		if cfg.IsSet("origins.0") {
+			t.Errorf("strict=%v: origins.0 should be replaced", strict)
+		}
+		if _, ok := cfg.Data()["db"]; ok {
+			t.Errorf("strict=%v: db scalar should be replaced by db.host", strict)
+		}
+		if got := MustGet[string](cfg, "db.host"); got != "h" {
+			t.Errorf("strict=%v: db.host = %q", strict, got)
+		}
+	}
+}
+
+func TestEmptyMapFilledIsNotAConflict(t *testing.T) {
+	cfg := New(
+		WithStrictKeys(),
+		WithDefaults(map[string]any{"plugins": map[string]any{}}),
+		WithDefaults(map[string]any{"plugins": map[string]any{"auth": true}}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cfg.Data()["plugins"]; ok {
+		t.Error("empty plugins map should be dropped once filled")
+	}
+}
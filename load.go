@@ -0,0 +1,251 @@
+package configo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devaloi/configo/provider"
+)
+
+// LoadPolicy decides what Load does when some providers fail.
+type LoadPolicy int
+
+const (
+	// FailFast returns the first provider error, in declaration order, and
+	// keeps the current configuration. It is the default.
+	FailFast LoadPolicy = iota
+	// BestEffort merges the providers that succeeded, publishes the result
+	// and returns a *LoadError listing the ones that failed.
+	BestEffort
+	// LastKnownGood is like BestEffort, but a failing provider contributes
+	// the data it returned on its last successful load.
+	LastKnownGood
+)
+
+// WithLoadPolicy sets how Load handles failing providers.
+func WithLoadPolicy(p LoadPolicy) Option {
+	return func(c *Config) {
+		c.loadPolicy = p
+	}
+}
+
+// WithContextProvider adds a provider whose Load honours cancellation.
+func WithContextProvider(p provider.ContextProvider) Option {
+	return func(c *Config) {
+		c.providers = append(c.providers, &contextProvider{p: p})
+	}
+}
+
+// WithProviderTimeout bounds how long Load waits for each provider. A
+// provider that does not finish in time fails with context.DeadlineExceeded.
+func WithProviderTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.providerTimeout = d
+	}
+}
+
+// LoadContext is like Load but stops waiting for providers once ctx is done.
+// Providers are loaded concurrently; their data is still merged in
+// declaration order.
+func (c *Config) LoadContext(ctx context.Context) error {
+	if c.root != nil {
+		return c.root.LoadContext(ctx)
+	}
+	err := c.load(ctx)
+	c.recordReload(err)
+	return err
+}
+
+func (c *Config) load(ctx context.Context) error {
+	if len(c.optErrs) > 0 {
+		return errors.Join(c.optErrs...)
+	}
+	profiles := c.Profiles()
+	groups := c.layers(profiles)
+	c.loadGroups(ctx, groups)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	var (
+		loaded   []loadedLayer
+		failures []ProviderError
+		good     = make(map[int][]loadedLayer)
+	)
+	for i, g := range groups {
+		layers, errs := g.collect(profiles, c.profilesConfigured())
+		if len(errs) == 0 {
+			loaded = append(loaded, layers...)
+			good[i] = layers
+			continue
+		}
+		if c.loadPolicy == FailFast {
+			return errs[0].Err
+		}
+		failures = append(failures, errs...)
+		if c.loadPolicy == LastKnownGood {
+			layers = c.lastGood[i]
+		}
+		loaded = append(loaded, layers...)
+	}
+
+	if err := c.commit(loaded); err != nil {
+		return err
+	}
+	if c.lastGood == nil {
+		c.lastGood = make(map[int][]loadedLayer)
+	}
+	for i, layers := range good {
+		c.lastGood[i] = layers
+	}
+	if len(failures) > 0 {
+		return &LoadError{Errors: failures}
+	}
+	return nil
+}
+
+// loadedLayer is the data one provider returned during Load.
+type loadedLayer struct {
+	provider provider.Provider
+	data     map[string]any
+}
+
+// layerGroup holds the providers a single declared provider expands into,
+// with their load results.
+type layerGroup struct {
+	source    provider.Provider
+	providers []provider.Provider
+	err       error // expansion error
+	data      []map[string]any
+	errs      []error
+}
+
+// layers expands every declared provider into its merge layers: directories
+// into one provider per file, and files into the base plus profile overlays.
+func (c *Config) layers(profiles []string) []*layerGroup {
+	groups := make([]*layerGroup, 0, len(c.providers))
+	for _, p := range c.providers {
+		g := &layerGroup{source: p}
+		switch lp := p.(type) {
+		case *profileFile:
+			g.providers = append([]provider.Provider{lp}, lp.overlays(profiles)...)
+		case provider.Layered:
+			g.providers, g.err = lp.Layers()
+		default:
+			g.providers = []provider.Provider{p}
+		}
+		g.data = make([]map[string]any, len(g.providers))
+		g.errs = make([]error, len(g.providers))
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// loadGroups loads every provider of every group concurrently.
+func (c *Config) loadGroups(ctx context.Context, groups []*layerGroup) {
+	var wg sync.WaitGroup
+	for _, g := range groups {
+		for i, p := range g.providers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				g.data[i], g.errs[i] = c.loadProvider(ctx, p)
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+// collect returns the group's layers in order, along with any provider
+// failures. If split is set, in-file profile sections become their own
+// layers.
+func (g *layerGroup) collect(profiles []string, split bool) ([]loadedLayer, []ProviderError) {
+	if g.err != nil {
+		return nil, []ProviderError{newProviderError(g.source, g.err)}
+	}
+	var (
+		loaded []loadedLayer
+		errs   []ProviderError
+	)
+	for i, p := range g.providers {
+		m, err := g.data[i], g.errs[i]
+		if err != nil {
+			errs = append(errs, newProviderError(p, err))
+			continue
+		}
+		if pf, ok := p.(*profileFile); ok && split {
+			var sections []loadedLayer
+			m, sections = pf.sections(m, profiles)
+			loaded = append(loaded, loadedLayer{provider: p, data: m})
+			loaded = append(loaded, sections...)
+			continue
+		}
+		loaded = append(loaded, loadedLayer{provider: p, data: m})
+	}
+	return loaded, errs
+}
+
+// contextProvider adapts a provider.ContextProvider to provider.Provider.
+type contextProvider struct {
+	p provider.ContextProvider
+}
+
+func (p *contextProvider) Load() (map[string]any, error) {
+	return p.p.Load(context.Background())
+}
+
+func (p *contextProvider) Name() string {
+	return providerName(p.p)
+}
+
+func (p *contextProvider) Describe(key string) string {
+	return describeKey(p.p, key)
+}
+
+// loadProvider loads p, giving up when ctx is done or the provider timeout
+// expires. A plain Provider cannot be interrupted, so when Load gives up on
+// one its call keeps running in the background until it returns.
+func (c *Config) loadProvider(ctx context.Context, p provider.Provider) (map[string]any, error) {
+	if c.providerTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.providerTimeout)
+		defer cancel()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%s provider: %w", providerName(p), err)
+	}
+	if cp, ok := p.(*contextProvider); ok {
+		return cp.p.Load(ctx)
+	}
+
+	type result struct {
+		data map[string]any
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		m, err := p.Load()
+		done <- result{data: m, err: err}
+	}()
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%s provider: %w", providerName(p), ctx.Err())
+	}
+}
+
+func newProviderError(p provider.Provider, err error) ProviderError {
+	src := Source{Provider: providerName(p), Location: describeKey(p, "")}
+	return ProviderError{Provider: src.String(), Err: err}
+}
+
+func (p *contextProvider) Paths() []string {
+	if w, ok := p.p.(provider.WatchableProvider); ok {
+		return w.Paths()
+	}
+	return nil
+}
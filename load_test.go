@@ -0,0 +1,179 @@
+package configo
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type slowProvider struct {
+	delay time.Duration
+	data  map[string]any
+}
+
+func (p *slowProvider) Load(ctx context.Context) (map[string]any, error) {
+	select {
+	case <-time.After(p.delay):
+		return p.data, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type blockingProvider struct {
+	release chan struct{}
+}
+
+func (p *blockingProvider) Load() (map[string]any, error) {
+	<-p.release
+	return map[string]any{}, nil
+}
+
+func TestLoadContextCancelled(t *testing.T) {
+	cfg := New(WithContextProvider(&slowProvider{delay: time.Minute}))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := cfg.LoadContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("LoadContext did not stop at the deadline")
+	}
+}
+
+func TestProviderTimeoutPlainProvider(t *testing.T) {
+	p := &blockingProvider{release: make(chan struct{})}
+	defer close(p.release)
+
+	cfg := New(WithProvider(p), WithProviderTimeout(50*time.Millisecond))
+	if err := cfg.Load(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestLoadConcurrentKeepsOrder(t *testing.T) {
+	var running, peak atomic.Int32
+	track := func(delay time.Duration, host string) *contextFunc {
+		return &contextFunc{fn: func(ctx context.Context) (map[string]any, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(delay)
+			return map[string]any{"host": host}, nil
+		}}
+	}
+
+	cfg := New(
+		WithContextProvider(track(150*time.Millisecond, "first")),
+		WithContextProvider(track(10*time.Millisecond, "second")),
+		WithContextProvider(track(100*time.Millisecond, "third")),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := MustGet[string](cfg, "host"); got != "third" {
+		t.Errorf("host = %q, want third (declaration order wins)", got)
+	}
+	if peak.Load() < 2 {
+		t.Error("expected providers to load concurrently")
+	}
+}
+
+type contextFunc struct {
+	fn func(ctx context.Context) (map[string]any, error)
+}
+
+func (p *contextFunc) Load(ctx context.Context) (map[string]any, error) {
+	return p.fn(ctx)
+}
+
+func (p *contextFunc) Name() string {
+	return "func"
+}
+
+func TestLoadPolicyFailFast(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"a": 1}),
+		WithFile("testdata/missing.yaml"),
+	)
+	err := cfg.Load()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	var le *LoadError
+	if errors.As(err, &le) {
+		t.Error("FailFast should return the provider error, not a LoadError")
+	}
+	if _, ok := cfg.Lookup("a"); ok {
+		t.Error("FailFast should not publish partial data")
+	}
+}
+
+func TestLoadPolicyBestEffort(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"a": 1}),
+		WithFile("testdata/missing.yaml"),
+		WithDir("testdata/missing.d"),
+		WithLoadPolicy(BestEffort),
+	)
+	err := cfg.Load()
+	var le *LoadError
+	if !errors.As(err, &le) {
+		t.Fatalf("expected LoadError, got %v", err)
+	}
+	if len(le.Errors) != 2 {
+		t.Fatalf("expected 2 provider errors, got %d: %v", len(le.Errors), le)
+	}
+	if le.Errors[0].Provider != "yaml (testdata/missing.yaml)" {
+		t.Errorf("Provider = %q", le.Errors[0].Provider)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Error("LoadError should unwrap to the provider errors")
+	}
+	if got := MustGet[int](cfg, "a"); got != 1 {
+		t.Errorf("a = %d, want 1 from the working provider", got)
+	}
+}
+
+func TestLoadPolicyLastKnownGood(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  host: good\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := New(
+		WithDefaults(map[string]any{"server.port": 80}),
+		WithFile(path),
+		WithLoadPolicy(LastKnownGood),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte("server: [broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := cfg.Load()
+	var le *LoadError
+	if !errors.As(err, &le) {
+		t.Fatalf("expected LoadError, got %v", err)
+	}
+	if got := MustGet[string](cfg, "server.host"); got != "good" {
+		t.Errorf("server.host = %q, want last known good value", got)
+	}
+	if v := cfg.Snapshot().Version(); v != 2 {
+		t.Errorf("version = %d, want 2 (partial load is still published)", v)
+	}
+}
@@ -0,0 +1,369 @@
+package configo
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MergeStrategy controls how a value from a higher layer is combined with
+// the value already merged from the layers below it.
+type MergeStrategy int
+
+const (
+	// Replace discards the lower value. Set on a map key, it drops the whole
+	// subtree from lower layers as soon as a higher layer defines any part of it.
+	Replace MergeStrategy = iota
+	// Append adds the higher layer's items after the lower ones.
+	Append
+	// Prepend adds the higher layer's items before the lower ones.
+	Prepend
+	// Union appends the higher layer's items that are not already present.
+	Union
+	// Deep merges map subtrees key by key. It is the default for maps.
+	Deep
+)
+
+var mergeStrategyNames = map[string]MergeStrategy{
+	"replace": Replace,
+	"append":  Append,
+	"prepend": Prepend,
+	"union":   Union,
+	"deep":    Deep,
+}
+
+func (s MergeStrategy) String() string {
+	for name, v := range mergeStrategyNames {
+		if v == s {
+			return name
+		}
+	}
+	return fmt.Sprintf("MergeStrategy(%d)", int(s))
+}
+
+// WithMergeStrategy sets the merge strategy for a single key. For slice
+// values it selects Replace, Append, Prepend or Union; for map subtrees it
+// selects Deep or Replace.
+func WithMergeStrategy(key string, s MergeStrategy) Option {
+	return func(c *Config) {
+		if c.strategies == nil {
+			c.strategies = make(map[string]MergeStrategy)
+		}
+		c.strategies[key] = s
+	}
+}
+
+// WithDefaultMergeStrategy sets the strategy used for slice values that have
+// no per-key strategy. Maps always deep-merge unless configured per key.
+func WithDefaultMergeStrategy(s MergeStrategy) Option {
+	return func(c *Config) {
+		c.defaultMerge = s
+	}
+}
+
+// WithMergeTags reads `merge` struct tags from target and registers a
+// per-key strategy for every field that has both `config` and `merge` tags:
+//
+//	Origins []string `config:"cors.origins" merge:"append"`
+func WithMergeTags(target any) Option {
+	return func(c *Config) {
+		t := reflect.TypeOf(target)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return
+		}
+		collectMergeTags(t, func(key string, s MergeStrategy) {
+			WithMergeStrategy(key, s)(c)
+		})
+	}
+}
+
+func collectMergeTags(t reflect.Type, register func(string, MergeStrategy)) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			collectMergeTags(field.Type, register)
+			continue
+		}
+		key := field.Tag.Get("config")
+		if key == "" {
+			continue
+		}
+		if s, ok := mergeStrategyNames[strings.TrimSpace(field.Tag.Get("merge"))]; ok {
+			register(key, s)
+		}
+	}
+}
+
+// mergeLayer merges one flattened provider layer into merged, recording
+// provenance for every key it sets. lists holds the keys of the layer's
+// slices, which are merged item by item according to their strategy.
+func (c *Config) mergeLayer(merged map[string]any, sources map[string][]Source, flat map[string]any, lists map[string]bool, source func(key string, v any) Source) {
+	for prefix, s := range c.strategies {
+		if s == Replace && touchesSubtree(flat, prefix) {
+			for k := range merged {
+				if inSubtree(k, prefix) {
+					delete(merged, k)
+					delete(sources, k)
+				}
+			}
+		}
+	}
+
+	// Lists are merged by their own strategy below, so a list only shadows
+	// lower scalars at its parents.
+	roots := outerLists(lists)
+	upper := make(map[string]any, len(flat))
+	for k, v := range flat {
+		if !slices.ContainsFunc(roots, func(root string) bool { return inSubtree(k, root) }) {
+			upper[k] = v
+		}
+	}
+	for _, root := range roots {
+		upper[root] = []any{}
+	}
+	shadow(merged, sources, upper)
+	for _, root := range roots {
+		c.mergeList(merged, sources, flat, root, source)
+	}
+	for k, v := range flat {
+		sources[k] = append(sources[k], source(k, v))
+		merged[k] = v
+	}
+}
+
+// shadow removes the lower-layer keys that the keys of a higher layer
+// replace: everything nested below a leaf it sets, and any leaf at a parent
+// of a key it sets. An empty map or list adds no keys, so it shadows nothing
+// below it.
+func shadow(merged map[string]any, sources map[string][]Source, upper map[string]any) {
+	for k := range merged {
+		for p := range parents(k) {
+			if v, ok := upper[p]; ok && !isEmptyContainer(v) {
+				delete(merged, k)
+				delete(sources, k)
+				break
+			}
+		}
+	}
+	for k := range upper {
+		for p := range parents(k) {
+			delete(merged, p)
+			delete(sources, p)
+		}
+	}
+}
+
+// parents yields every proper parent of key, outermost first: "a", then
+// "a.b" for "a.b.c".
+func parents(key string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for i := range len(key) {
+			if key[i] == '.' && !yield(key[:i]) {
+				return
+			}
+		}
+	}
+}
+
+// listItem is one element of a flattened list: its leaves keyed relative to
+// the element ("" for a scalar element) and their provenance.
+type listItem struct {
+	entries map[string]any
+	sources map[string][]Source
+}
+
+func (it listItem) value() any {
+	if v, ok := it.entries[""]; ok && len(it.entries) == 1 {
+		return v
+	}
+	return listify(Unflatten(it.entries))
+}
+
+// mergeList combines the list at root in the layer with the list merged from
+// lower layers, then re-indexes the result. The layer's entries for root are
+// removed from flat.
+func (c *Config) mergeList(merged map[string]any, sources map[string][]Source, flat map[string]any, root string, source func(key string, v any) Source) {
+	s, ok := c.strategies[root]
+	if !ok {
+		s = c.defaultMerge
+	}
+
+	upper := takeItems(flat, nil, root, func(k string, v any) []Source {
+		return []Source{source(k, v)}
+	})
+	empty, hasEmpty := flat[root]
+	delete(flat, root)
+
+	var lower []listItem
+	if s == Append || s == Prepend || s == Union {
+		if isList(merged, root) {
+			lower = takeItems(merged, sources, root, func(k string, _ any) []Source { return sources[k] })
+		}
+	}
+	previous := make(map[string][]Source)
+	for k := range merged {
+		if inSubtree(k, root) {
+			previous[k] = sources[k]
+			delete(merged, k)
+			delete(sources, k)
+		}
+	}
+
+	var items []listItem
+	switch s {
+	case Append:
+		items = append(lower, upper...)
+	case Prepend:
+		items = append(upper, lower...)
+	case Union:
+		items = lower
+		for _, it := range upper {
+			if !containsItem(items, it) {
+				items = append(items, it)
+			}
+		}
+	default:
+		// Each replacing item keeps the history of the index it lands on.
+		for i, it := range upper {
+			for rel := range it.entries {
+				key := itemKey(root, i, rel)
+				it.sources[rel] = append(previous[key], it.sources[rel]...)
+			}
+		}
+		items = upper
+	}
+
+	if len(items) == 0 {
+		if !hasEmpty {
+			empty = []any{}
+		}
+		merged[root] = empty
+		sources[root] = append(previous[root], source(root, empty))
+		return
+	}
+	for i, it := range items {
+		for rel, v := range it.entries {
+			key := itemKey(root, i, rel)
+			merged[key] = v
+			sources[key] = it.sources[rel]
+		}
+	}
+}
+
+// takeItems removes the entries below root from flat, and from sources if
+// it is not nil, and groups them by list index.
+func takeItems(flat map[string]any, sources map[string][]Source, root string, history func(string, any) []Source) []listItem {
+	byIndex := make(map[int]listItem)
+	for k, v := range flat {
+		rel, ok := strings.CutPrefix(k, root+".")
+		if !ok {
+			continue
+		}
+		i, rest, ok := listIndex(rel)
+		if !ok {
+			continue
+		}
+		it, ok := byIndex[i]
+		if !ok {
+			it = listItem{entries: make(map[string]any), sources: make(map[string][]Source)}
+			byIndex[i] = it
+		}
+		it.entries[rest] = v
+		it.sources[rest] = history(k, v)
+		delete(flat, k)
+		if sources != nil {
+			delete(sources, k)
+		}
+	}
+	items := make([]listItem, 0, len(byIndex))
+	for _, i := range sortedIndexes(byIndex) {
+		items = append(items, byIndex[i])
+	}
+	return items
+}
+
+func itemKey(root string, i int, rel string) string {
+	key := root + "." + strconv.Itoa(i)
+	if rel != "" {
+		key += "." + rel
+	}
+	return key
+}
+
+// isList reports whether merged holds a non-empty list below root.
+func isList(merged map[string]any, root string) bool {
+	found := false
+	for k := range merged {
+		if rel, ok := strings.CutPrefix(k, root+"."); ok {
+			if _, _, ok := listIndex(rel); !ok {
+				return false
+			}
+			found = true
+		}
+	}
+	return found
+}
+
+// outerLists returns the list keys that are not nested inside another list,
+// in sorted order.
+func outerLists(lists map[string]bool) []string {
+	var out []string
+	for _, k := range sortedKeys(lists) {
+		nested := false
+		for _, outer := range out {
+			if inSubtree(k, outer) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+func containsItem(items []listItem, it listItem) bool {
+	v := it.value()
+	for _, item := range items {
+		if reflect.DeepEqual(item.value(), v) {
+			return true
+		}
+	}
+	return false
+}
+
+func toAnySlice(v any) ([]any, bool) {
+	if s, ok := v.([]any); ok {
+		return s, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out, true
+}
+
+func touchesSubtree(flat map[string]any, prefix string) bool {
+	for k := range flat {
+		if inSubtree(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// inSubtree reports whether key is prefix itself or nested below it.
+func inSubtree(key, prefix string) bool {
+	return key == prefix || strings.HasPrefix(key, prefix+".")
+}
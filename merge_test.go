@@ -0,0 +1,192 @@
+package configo
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMergeStrategySlices(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		want     any
+	}{
+		{"replace", Replace, []any{"b", "c"}},
+		{"append", Append, []any{"a", "b", "b", "c"}},
+		{"prepend", Prepend, []any{"b", "c", "a", "b"}},
+		{"union", Union, []any{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New(
+				WithDefaults(map[string]any{"cors": map[string]any{"origins": []any{"a", "b"}}}),
+				WithDefaults(map[string]any{"cors": map[string]any{"origins": []string{"b", "c"}}}),
+				WithMergeStrategy("cors.origins", tt.strategy),
+			)
+			if err := cfg.Load(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, _ := cfg.Lookup("cors.origins")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cors.origins = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeDefaultStrategy(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"tags": []any{"web"}}),
+		WithDefaults(map[string]any{"tags": []any{"api"}}),
+		WithDefaultMergeStrategy(Append),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := Get[[]string](cfg, "tags")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"web", "api"}) {
+		t.Errorf("tags = %v, want [web api]", got)
+	}
+}
+
+func TestMergeReplaceSubtree(t *testing.T) {
+	base := map[string]any{"limits": map[string]any{"cpu": 2, "memory": "1Gi"}}
+	override := map[string]any{"limits": map[string]any{"cpu": 4}}
+
+	cfg := New(WithDefaults(base), WithDefaults(override))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cfg.Data()["limits.memory"]; !ok {
+		t.Error("deep merge should keep limits.memory")
+	}
+
+	cfg = New(WithDefaults(base), WithDefaults(override), WithMergeStrategy("limits", Replace))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := cfg.Data()
+	if _, ok := data["limits.memory"]; ok {
+		t.Error("replace should drop limits.memory from the lower layer")
+	}
+	if data["limits.cpu"] != 4 {
+		t.Errorf("limits.cpu = %v, want 4", data["limits.cpu"])
+	}
+	if n := len(cfg.Explain("limits.cpu")); n != 1 {
+		t.Errorf("expected 1 layer after subtree replace, got %d", n)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	type CORS struct {
+		Origins []string `config:"cors.origins" merge:"union"`
+	}
+	cfg := New(
+		WithDefaults(map[string]any{"cors.origins": []any{"a", "b"}}),
+		WithDefaults(map[string]any{"cors.origins": []any{"b", "c"}}),
+		WithMergeTags(&CORS{}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var cors CORS
+	if err := cfg.Bind(&cors); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cors.Origins, []string{"a", "b", "c"}) {
+		t.Errorf("Origins = %v, want [a b c]", cors.Origins)
+	}
+
+	layers := cfg.Explain("cors.origins.2")
+	if len(layers) != 1 || layers[0].Layer != 1 || layers[0].Value != "c" {
+		t.Errorf("Explain should report the layer that added the item, got %v", layers)
+	}
+}
+
+func TestMergeListItemOverride(t *testing.T) {
+	t.Setenv("APP_SERVERS_1_HOST", "b.internal")
+	cfg := New(
+		WithDefaults(map[string]any{"servers": []any{
+			map[string]any{"host": "a", "port": 80},
+			map[string]any{"host": "b", "port": 81},
+		}}),
+		WithEnvPrefix("APP"),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := MustGet[string](cfg, "servers.1.host"); got != "b.internal" {
+		t.Errorf("servers.1.host = %q, want b.internal", got)
+	}
+	if got := MustGet[string](cfg, "servers[0].host"); got != "a" {
+		t.Errorf("servers[0].host = %q, want a", got)
+	}
+	want := []any{
+		map[string]any{"host": "a", "port": 80},
+		map[string]any{"host": "b.internal", "port": 81},
+	}
+	if got, _ := cfg.Lookup("servers"); !reflect.DeepEqual(got, want) {
+		t.Errorf("servers = %v, want %v", got, want)
+	}
+	src, _ := cfg.Source("servers.1.host")
+	if src.Location != "APP_SERVERS_1_HOST" {
+		t.Errorf("Source = %v, want env (APP_SERVERS_1_HOST)", src)
+	}
+}
+
+func TestMergeListReplacesWholeList(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"servers": []any{
+			map[string]any{"host": "a"},
+			map[string]any{"host": "b"},
+			map[string]any{"host": "c"},
+		}}),
+		WithDefaults(map[string]any{"servers": []any{map[string]any{"host": "z"}}}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []any{map[string]any{"host": "z"}}
+	if got, _ := cfg.Lookup("servers"); !reflect.DeepEqual(got, want) {
+		t.Errorf("servers = %v, want %v", got, want)
+	}
+
+	if err := cfg.Set("servers", []any{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cfg.Lookup("servers"); !reflect.DeepEqual(got, []any{}) {
+		t.Errorf("servers after Set = %v, want []", got)
+	}
+}
+
+func TestMergeAppendListOfMaps(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"servers": []any{map[string]any{"host": "a"}}}),
+		WithDefaults(map[string]any{"servers": []any{map[string]any{"host": "b"}}}),
+		WithMergeStrategy("servers", Append),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type Server struct {
+		Host string `config:"host"`
+	}
+	var hosts []string
+	for i := range 2 {
+		var s Server
+		if err := cfg.Snapshot().Sub(fmt.Sprintf("servers.%d", i)).Bind(&s); err != nil {
+			t.Fatal(err)
+		}
+		hosts = append(hosts, s.Host)
+	}
+	if !reflect.DeepEqual(hosts, []string{"a", "b"}) {
+		t.Errorf("hosts = %v, want [a b]", hosts)
+	}
+}
@@ -0,0 +1,119 @@
+package configo
+
+import "maps"
+
+// Set overrides key with value in an in-memory layer above every provider.
+// Overrides survive Load and Watch reloads until removed with Unset or
+// ClearOverrides. A map value overrides each of its nested keys; a slice
+// value replaces the whole list.
+// OnChange subscribers are notified.
+func (c *Config) Set(key string, value any) error {
+	if c.root != nil {
+		return c.root.Set(c.fullKey(key), value)
+	}
+	key, _, _ = c.resolveAlias(c.fullKey(key))
+	return c.updateOverrides(func() {
+		if c.overrides == nil {
+			c.overrides = make(map[string]any)
+		}
+		c.deleteOverrides(key)
+		delete(c.masked, key)
+		f := newFlattener()
+		f.value(key, key, value)
+		for k, v := range f.out {
+			c.overrides[c.normalizer.apply(k)] = v
+		}
+		// A list replaces the lower list instead of overriding it item by item.
+		for k := range f.lists {
+			if c.masked == nil {
+				c.masked = make(map[string]bool)
+			}
+			c.masked[c.normalizer.apply(k)] = true
+		}
+	})
+}
+
+// Unset removes any override for key and masks key, and everything nested
+// below it, in all lower layers until the mask is cleared by Set or
+// ClearOverrides. OnChange subscribers are notified.
+func (c *Config) Unset(key string) error {
+	if c.root != nil {
+		return c.root.Unset(c.fullKey(key))
+	}
+	key, _, _ = c.resolveAlias(c.fullKey(key))
+	return c.updateOverrides(func() {
+		if c.masked == nil {
+			c.masked = make(map[string]bool)
+		}
+		c.deleteOverrides(key)
+		c.masked[key] = true
+	})
+}
+
+// ClearOverrides removes every override and mask set with Set or Unset.
+// OnChange subscribers are notified.
+func (c *Config) ClearOverrides() error {
+	if c.root != nil {
+		return c.root.ClearOverrides()
+	}
+	return c.updateOverrides(func() {
+		c.overrides = nil
+		c.masked = nil
+	})
+}
+
+// Overrides returns a copy of the current override layer.
+func (c *Config) Overrides() map[string]any {
+	r := c.base()
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	out := make(map[string]any)
+	for k, v := range r.overrides {
+		if rel, ok := c.relKey(k); ok {
+			out[rel] = v
+		}
+	}
+	return out
+}
+
+// updateOverrides applies change and re-merges the last loaded layers. On
+// failure the previous overrides are restored.
+func (c *Config) updateOverrides(change func()) error {
+	c.writeMu.Lock()
+	old := c.snap.Load()
+	prevOverrides, prevMasked := maps.Clone(c.overrides), maps.Clone(c.masked)
+	change()
+	if err := c.commit(c.loaded); err != nil {
+		c.overrides, c.masked = prevOverrides, prevMasked
+		c.writeMu.Unlock()
+		return err
+	}
+	c.writeMu.Unlock()
+	c.notify(old)
+	return nil
+}
+
+func (c *Config) deleteOverrides(key string) {
+	for k := range c.overrides {
+		if inSubtree(k, key) {
+			delete(c.overrides, k)
+		}
+	}
+}
+
+// applyOverrides masks unset keys and merges the override layer on top.
+func (c *Config) applyOverrides(merged map[string]any, sources map[string][]Source, layer int) {
+	for mask := range c.masked {
+		for k := range merged {
+			if inSubtree(k, mask) {
+				delete(merged, k)
+				delete(sources, k)
+			}
+		}
+	}
+	shadow(merged, sources, c.overrides)
+	for k, v := range c.overrides {
+		merged[k] = v
+		sources[k] = append(sources[k], Source{Provider: "override", Layer: layer, Value: v})
+	}
+}
@@ -0,0 +1,136 @@
+package configo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetOverridesAllLayers(t *testing.T) {
+	t.Setenv("OVR_SERVER_HOST", "envhost")
+	cfg := New(WithFile("testdata/config.yaml"), WithEnvPrefix("OVR"))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var notified int
+	cfg.OnChange(func(*Config) { notified++ })
+
+	if err := cfg.Set("server.host", "admin-host"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := MustGet[string](cfg, "server.host"); got != "admin-host" {
+		t.Errorf("server.host = %q, want admin-host", got)
+	}
+	if src, _ := cfg.Source("server.host"); src.Provider != "override" {
+		t.Errorf("source = %v, want override", src)
+	}
+	if notified != 1 {
+		t.Errorf("expected 1 notification, got %d", notified)
+	}
+
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := MustGet[string](cfg, "server.host"); got != "admin-host" {
+		t.Errorf("override should survive reload, got %q", got)
+	}
+
+	if err := cfg.ClearOverrides(); err != nil {
+		t.Fatal(err)
+	}
+	if got := MustGet[string](cfg, "server.host"); got != "envhost" {
+		t.Errorf("server.host = %q, want envhost after ClearOverrides", got)
+	}
+	if notified != 2 {
+		t.Errorf("expected 2 notifications, got %d", notified)
+	}
+}
+
+func TestUnsetMasksLowerLayers(t *testing.T) {
+	cfg := New(WithFile("testdata/config.yaml"))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.Unset("server.debug"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get[bool](cfg, "server.debug"); err == nil {
+		t.Error("server.debug should be masked")
+	}
+
+	if err := cfg.Unset("database"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("database.host", "only-host"); err != nil {
+		t.Fatal(err)
+	}
+	data := cfg.Sub("database").Data()
+	if len(data) != 1 || data["host"] != "only-host" {
+		t.Errorf("database = %v, want only host override", data)
+	}
+
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get[bool](cfg, "server.debug"); err == nil {
+		t.Error("mask should survive reload")
+	}
+}
+
+func TestSetMapAndSub(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{"cache.ttl": "1m"}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	cache := cfg.Sub("cache")
+	if err := cache.Set("limits", map[string]any{"items": 100}); err != nil {
+		t.Fatal(err)
+	}
+	if got := MustGet[int](cfg, "cache.limits.items"); got != 100 {
+		t.Errorf("cache.limits.items = %d, want 100", got)
+	}
+	if got := cfg.Overrides(); len(got) != 1 || got["cache.limits.items"] != 100 {
+		t.Errorf("Overrides = %v", got)
+	}
+}
+
+func TestSetFailureRestoresOverrides(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{"a": "x"}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("a", "${missing}"); err == nil {
+		t.Fatal("expected interpolation error")
+	}
+	if got := MustGet[string](cfg, "a"); got != "x" {
+		t.Errorf("a = %q, want x", got)
+	}
+	if len(cfg.Overrides()) != 0 {
+		t.Error("failed Set should not keep the override")
+	}
+}
+
+func TestSetSurvivesFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("log:\n  level: info\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := New(WithFile(path))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("log.level", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("log:\n  level: warn\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := MustGet[string](cfg, "log.level"); got != "debug" {
+		t.Errorf("log.level = %q, want debug", got)
+	}
+}
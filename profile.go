@@ -0,0 +1,166 @@
+package configo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/devaloi/configo/provider"
+)
+
+// WithProfile activates one or more profiles, in order. A profile list may
+// also be given as a single comma-separated string such as "prod,eu-west".
+//
+// For every file added with WithFile or WithOptionalFile, each active
+// profile adds an optional overlay next to it (config.yaml is followed by
+// config.prod.yaml) and merges the file's own profiles.<name> section. The
+// profiles map is only treated this way once WithProfile or WithProfileEnv
+// is set.
+func WithProfile(names ...string) Option {
+	return func(c *Config) {
+		for _, name := range names {
+			c.profiles = append(c.profiles, splitProfiles(name)...)
+		}
+	}
+}
+
+// WithProfileEnv activates the comma-separated profiles named by an
+// environment variable such as APP_PROFILE. They are read on every Load and
+// follow any profiles set with WithProfile.
+func WithProfileEnv(name string) Option {
+	return func(c *Config) {
+		c.profileEnv = name
+	}
+}
+
+// profilesConfigured reports whether WithProfile or WithProfileEnv was used.
+// Only then is a top-level profiles map read as per-profile sections;
+// otherwise it is ordinary configuration data.
+func (c *Config) profilesConfigured() bool {
+	r := c.base()
+	return len(r.profiles) > 0 || r.profileEnv != ""
+}
+
+// Profiles returns the active profiles in the order they are applied.
+func (c *Config) Profiles() []string {
+	r := c.base()
+	out := append([]string(nil), r.profiles...)
+	if r.profileEnv != "" {
+		for _, name := range splitProfiles(os.Getenv(r.profileEnv)) {
+			if !containsString(out, name) {
+				out = append(out, name)
+			}
+		}
+	}
+	return out
+}
+
+func splitProfiles(s string) []string {
+	var out []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// profileFile marks a file added with WithFile or WithOptionalFile as the
+// base for profile overlays and in-file profile sections.
+type profileFile struct {
+	provider.Provider
+	path string
+}
+
+func (p *profileFile) Name() string {
+	return providerName(p.Provider)
+}
+
+func (p *profileFile) Describe(key string) string {
+	return describeKey(p.Provider, key)
+}
+
+func (p *profileFile) Paths() []string {
+	if w, ok := p.Provider.(provider.WatchableProvider); ok {
+		return w.Paths()
+	}
+	return nil
+}
+
+// overlays returns an optional provider for each profile's overlay file.
+func (p *profileFile) overlays(profiles []string) []provider.Provider {
+	ext := filepath.Ext(p.path)
+	base := strings.TrimSuffix(p.path, ext)
+	out := make([]provider.Provider, 0, len(profiles))
+	for _, name := range profiles {
+		if fp := provider.ForPath(base + "." + name + ext); fp != nil {
+			out = append(out, provider.NewOptional(fp))
+		}
+	}
+	return out
+}
+
+// sections removes the profiles section from m and returns the subtree of
+// every active profile as its own layer, in profile order.
+func (p *profileFile) sections(m map[string]any, profiles []string) (map[string]any, []loadedLayer) {
+	raw, ok := m["profiles"]
+	if !ok {
+		return m, nil
+	}
+	all, ok := raw.(map[string]any)
+	if !ok {
+		if am, isAny := raw.(map[any]any); isAny {
+			all, ok = convertMap(am), true
+		}
+	}
+	if !ok {
+		return m, nil
+	}
+
+	base := make(map[string]any, len(m)-1)
+	for k, v := range m {
+		if k != "profiles" {
+			base[k] = v
+		}
+	}
+	var out []loadedLayer
+	for _, name := range profiles {
+		section, ok := all[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		out = append(out, loadedLayer{
+			provider: &profileSection{parent: p, profile: name, data: section},
+			data:     section,
+		})
+	}
+	return base, out
+}
+
+// profileSection is the profiles.<name> subtree of a base file.
+type profileSection struct {
+	parent  *profileFile
+	profile string
+	data    map[string]any
+}
+
+func (p *profileSection) Load() (map[string]any, error) {
+	return p.data, nil
+}
+
+func (p *profileSection) Name() string {
+	return p.parent.Name()
+}
+
+func (p *profileSection) Describe(key string) string {
+	return p.parent.Describe(key) + " [profiles." + p.profile + "]"
+}
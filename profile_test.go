@@ -0,0 +1,125 @@
+package configo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeProfileFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.yaml": `server:
+  host: localhost
+  port: 8080
+log:
+  level: debug
+profiles:
+  prod:
+    log:
+      level: warn
+`,
+		"config.prod.yaml": `server:
+  host: prod.example.com
+`,
+		"config.eu-west.yaml": `server:
+  host: eu-west.example.com
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "config.yaml")
+}
+
+func TestProfileOverlay(t *testing.T) {
+	path := writeProfileFiles(t)
+
+	cfg := New(WithFile(path), WithProfile("prod"))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := cfg.Data()
+	if got := data["server.host"]; got != "prod.example.com" {
+		t.Errorf("server.host = %v, want prod.example.com", got)
+	}
+	if got := data["server.port"]; got != 8080 {
+		t.Errorf("server.port = %v, want 8080", got)
+	}
+	if got := data["log.level"]; got != "warn" {
+		t.Errorf("log.level = %v, want warn (in-file profile section)", got)
+	}
+	if _, ok := data["profiles.prod.log.level"]; ok {
+		t.Error("profiles section should not be merged as data")
+	}
+
+	src, _ := cfg.Source("log.level")
+	if want := path + " [profiles.prod]"; src.Location != want {
+		t.Errorf("source location = %q, want %q", src.Location, want)
+	}
+}
+
+func TestProfileOrder(t *testing.T) {
+	path := writeProfileFiles(t)
+
+	cfg := New(WithFile(path), WithProfile("prod,eu-west"))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Data()["server.host"]; got != "eu-west.example.com" {
+		t.Errorf("server.host = %v, want eu-west.example.com (later profile wins)", got)
+	}
+}
+
+func TestProfileEnv(t *testing.T) {
+	path := writeProfileFiles(t)
+	t.Setenv("TEST_PROFILE", "staging, prod")
+
+	cfg := New(WithFile(path), WithProfileEnv("TEST_PROFILE"))
+	if got := cfg.Profiles(); len(got) != 2 || got[0] != "staging" || got[1] != "prod" {
+		t.Errorf("Profiles() = %v, want [staging prod]", got)
+	}
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("missing overlay should be skipped: %v", err)
+	}
+	if got := cfg.Data()["server.host"]; got != "prod.example.com" {
+		t.Errorf("server.host = %v, want prod.example.com", got)
+	}
+}
+
+func TestNoProfileIgnoresOverlays(t *testing.T) {
+	path := writeProfileFiles(t)
+
+	cfg := New(WithFile(path))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := cfg.Data()
+	if got := data["server.host"]; got != "localhost" {
+		t.Errorf("server.host = %v, want localhost", got)
+	}
+	if got := data["log.level"]; got != "debug" {
+		t.Errorf("log.level = %v, want debug", got)
+	}
+	if got := data["profiles.prod.log.level"]; got != "warn" {
+		t.Errorf("profiles.prod.log.level = %v, want the section kept as data", got)
+	}
+}
+
+func TestPlainProfilesMapSurvives(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("profiles:\n  admin:\n    role: root\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := New(WithFile(path))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := GetOr(cfg, "profiles.admin.role", ""); got != "root" {
+		t.Errorf("profiles.admin.role = %q, want root; keys = %v", got, cfg.Keys())
+	}
+}
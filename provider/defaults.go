@@ -12,3 +12,7 @@ func NewDefaults(values map[string]any) *Defaults {
 func (p *Defaults) Load() (map[string]any, error) {
 	return p.Values, nil
 }
+
+func (p *Defaults) Name() string {
+	return "defaults"
+}
@@ -44,3 +44,15 @@ func (p *DotEnv) Load() (map[string]any, error) {
 	}
 	return out, nil
 }
+
+func (p *DotEnv) Name() string {
+	return "dotenv"
+}
+
+func (p *DotEnv) Describe(string) string {
+	return p.Path
+}
+
+func (p *DotEnv) Paths() []string {
+	return []string{p.Path}
+}
@@ -32,3 +32,12 @@ func (p *Env) Load() (map[string]any, error) {
 	}
 	return out, nil
 }
+
+func (p *Env) Name() string {
+	return "env"
+}
+
+// Describe returns the environment variable that maps to key.
+func (p *Env) Describe(key string) string {
+	return p.Prefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
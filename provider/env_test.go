@@ -44,3 +44,10 @@ func TestEnvProviderNoMatch(t *testing.T) {
 		t.Error("should not include non-prefixed keys")
 	}
 }
+
+func TestEnvProviderDescribe(t *testing.T) {
+	p := NewEnv("MYAPP")
+	if got := p.Describe("database.host"); got != "MYAPP_DATABASE_HOST" {
+		t.Errorf("Describe = %q, want MYAPP_DATABASE_HOST", got)
+	}
+}
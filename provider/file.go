@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// ForPath returns the file provider matching the extension of path
+// (.yaml/.yml, .json, .toml or .env), or nil if the format is not supported.
+func ForPath(path string) Provider {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return NewYAML(path)
+	case ".json":
+		return NewJSON(path)
+	case ".toml":
+		return NewTOML(path)
+	case ".env":
+		return NewDotEnv(path)
+	}
+	return nil
+}
+
+// Optional wraps a file provider so that a missing file loads as empty
+// instead of failing. Any other error, such as a parse error, is returned.
+type Optional struct {
+	Provider Provider
+}
+
+func NewOptional(p Provider) *Optional {
+	return &Optional{Provider: p}
+}
+
+func (p *Optional) Load() (map[string]any, error) {
+	m, err := p.Provider.Load()
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]any{}, nil
+	}
+	return m, err
+}
+
+func (p *Optional) Name() string {
+	if n, ok := p.Provider.(Named); ok {
+		return n.Name()
+	}
+	return "optional"
+}
+
+func (p *Optional) Describe(key string) string {
+	if d, ok := p.Provider.(Describer); ok {
+		return d.Describe(key)
+	}
+	return ""
+}
+
+func (p *Optional) Paths() []string {
+	if w, ok := p.Provider.(WatchableProvider); ok {
+		return w.Paths()
+	}
+	return nil
+}
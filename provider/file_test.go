@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestForPath(t *testing.T) {
+	tests := map[string]string{
+		"config.yaml": "yaml",
+		"config.YML":  "yaml",
+		"config.json": "json",
+		"config.toml": "toml",
+		"local.env":   "dotenv",
+		".env":        "dotenv",
+	}
+	for path, want := range tests {
+		p := ForPath(path)
+		if p == nil {
+			t.Errorf("ForPath(%q) = nil, want %s provider", path, want)
+			continue
+		}
+		if got := p.(Named).Name(); got != want {
+			t.Errorf("ForPath(%q) = %s provider, want %s", path, got, want)
+		}
+	}
+	if p := ForPath("config.ymal"); p != nil {
+		t.Errorf("ForPath(config.ymal) = %T, want nil", p)
+	}
+}
+
+func TestOptionalMissingFile(t *testing.T) {
+	p := NewOptional(NewYAML("nonexistent.yaml"))
+	m, err := p.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m) != 0 {
+		t.Errorf("expected empty map, got %v", m)
+	}
+	if p.Name() != "yaml" {
+		t.Errorf("Name = %q, want yaml", p.Name())
+	}
+}
+
+func TestOptionalParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := NewOptional(NewJSON(path))
+	if _, err := p.Load(); err == nil {
+		t.Fatal("expected parse error for existing file")
+	}
+}
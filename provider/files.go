@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Files loads every supported config file matching a glob pattern, in
+// lexical order. Files with unsupported extensions are ignored.
+type Files struct {
+	Pattern string
+	Dir     string
+}
+
+func NewFiles(pattern string) *Files {
+	return &Files{Pattern: pattern}
+}
+
+// NewDir loads every .yaml/.yml, .json, .toml and .env file in dir, such as
+// a conf.d directory of numbered fragments. The directory must exist.
+func NewDir(dir string) *Files {
+	return &Files{Pattern: filepath.Join(dir, "*"), Dir: dir}
+}
+
+// Layers returns one provider per matching file, in lexical order.
+func (p *Files) Layers() ([]Provider, error) {
+	if p.Dir != "" {
+		if _, err := os.Stat(p.Dir); err != nil {
+			return nil, fmt.Errorf("files provider: %w", err)
+		}
+	}
+	matches, err := filepath.Glob(p.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("files provider: %w", err)
+	}
+	sort.Strings(matches)
+
+	var out []Provider
+	for _, path := range matches {
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		if fp := ForPath(path); fp != nil {
+			out = append(out, fp)
+		}
+	}
+	return out, nil
+}
+
+// Paths returns the directory that holds the matching files, so that
+// added, changed and removed fragments are all noticed. If the directory
+// part of the pattern has glob metacharacters, as in conf/*/app.yaml, it
+// returns the deepest directory above them and the directory of every
+// current match.
+func (p *Files) Paths() []string {
+	if p.Dir != "" {
+		return []string{p.Dir}
+	}
+	dir := filepath.Dir(p.Pattern)
+	if !hasMeta(dir) {
+		return []string{dir}
+	}
+	root := dir
+	for hasMeta(root) {
+		root = filepath.Dir(root)
+	}
+	out := []string{root}
+	matches, _ := filepath.Glob(p.Pattern)
+	sort.Strings(matches)
+	for _, m := range matches {
+		if d := filepath.Dir(m); !slices.Contains(out, d) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// hasMeta reports whether path contains any of the magic characters
+// recognized by filepath.Match.
+func hasMeta(path string) bool {
+	magic := `*?[`
+	if runtime.GOOS != "windows" {
+		magic = `*?[\`
+	}
+	return strings.ContainsAny(path, magic)
+}
+
+// Load merges all matching files into a single map, later files winning.
+func (p *Files) Load() (map[string]any, error) {
+	layers, err := p.Layers()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]any)
+	for _, l := range layers {
+		m, err := l.Load()
+		if err != nil {
+			return nil, err
+		}
+		mergeMaps(out, m)
+	}
+	return out, nil
+}
+
+func (p *Files) Name() string {
+	return "files"
+}
+
+func (p *Files) Describe(string) string {
+	return p.Pattern
+}
+
+func mergeMaps(dst, src map[string]any) {
+	for k, v := range src {
+		if sv, ok := v.(map[string]any); ok {
+			if dv, ok := dst[k].(map[string]any); ok {
+				mergeMaps(dv, sv)
+				continue
+			}
+			copied := make(map[string]any, len(sv))
+			mergeMaps(copied, sv)
+			dst[k] = copied
+			continue
+		}
+		dst[k] = v
+	}
+}
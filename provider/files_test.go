@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDirLayersLexicalOrder(t *testing.T) {
+	p := NewDir(testdataPath("conf.d"))
+	layers, err := p.Layers()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"yaml", "toml", "dotenv"}
+	if len(layers) != len(want) {
+		t.Fatalf("expected %d layers, got %d", len(want), len(layers))
+	}
+	for i, name := range want {
+		if got := layers[i].(Named).Name(); got != name {
+			t.Errorf("layer %d = %s, want %s", i, got, name)
+		}
+	}
+}
+
+func TestDirLoadMergesFiles(t *testing.T) {
+	m, err := NewDir(testdataPath("conf.d")).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server, ok := m["server"].(map[string]any)
+	if !ok {
+		t.Fatal("missing server key")
+	}
+	if server["host"] != "localhost" {
+		t.Errorf("server.host = %v, want localhost", server["host"])
+	}
+	if server["port"] != int64(9090) {
+		t.Errorf("server.port = %v (%T), want 9090", server["port"], server["port"])
+	}
+}
+
+func TestDirMissing(t *testing.T) {
+	if _, err := NewDir("nonexistent.d").Layers(); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+}
+
+func TestFilesGlob(t *testing.T) {
+	layers, err := NewFiles(testdataPath("config.*")).Layers()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(layers) != 3 {
+		t.Errorf("expected 3 layers, got %d", len(layers))
+	}
+}
+
+func TestFilesPaths(t *testing.T) {
+	if got := NewDir("conf.d").Paths(); len(got) != 1 || got[0] != "conf.d" {
+		t.Errorf("Dir Paths = %v, want [conf.d]", got)
+	}
+	if got := NewFiles(filepath.Join("config", "*.yaml")).Paths(); len(got) != 1 || got[0] != "config" {
+		t.Errorf("Files Paths = %v, want [config]", got)
+	}
+	if got := NewOptional(NewYAML("app.yaml")).Paths(); len(got) != 1 || got[0] != "app.yaml" {
+		t.Errorf("Optional Paths = %v, want [app.yaml]", got)
+	}
+}
+
+func TestFilesPathsGlobDirectory(t *testing.T) {
+	root := t.TempDir()
+	for _, env := range []string{"dev", "prod"} {
+		dir := filepath.Join(root, "conf", env)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("a: 1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := NewFiles(filepath.Join(root, "conf", "*", "app.yaml")).Paths()
+	want := []string{
+		filepath.Join(root, "conf"),
+		filepath.Join(root, "conf", "dev"),
+		filepath.Join(root, "conf", "prod"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Paths = %v, want %v", got, want)
+	}
+}
@@ -28,3 +28,12 @@ func RegisterFlags(fs *flag.FlagSet, keys ...string) {
 		fs.String(key, "", fmt.Sprintf("config value for %s", key))
 	}
 }
+
+func (p *Flag) Name() string {
+	return "flag"
+}
+
+// Describe returns the command-line flag that maps to key.
+func (p *Flag) Describe(key string) string {
+	return "--" + key
+}
@@ -26,3 +26,15 @@ func (p *JSON) Load() (map[string]any, error) {
 	}
 	return raw, nil
 }
+
+func (p *JSON) Name() string {
+	return "json"
+}
+
+func (p *JSON) Describe(string) string {
+	return p.Path
+}
+
+func (p *JSON) Paths() []string {
+	return []string{p.Path}
+}
@@ -1,6 +1,49 @@
 package provider
 
+import "context"
+
 // Provider loads configuration from a source and returns a flat map.
 type Provider interface {
 	Load() (map[string]any, error)
 }
+
+// Named is implemented by providers that report a short type name such as
+// "yaml" or "env". It is used when explaining where a value came from.
+type Named interface {
+	Name() string
+}
+
+// Describer is implemented by providers that can say where a single key was
+// read from: a file path, an environment variable name or a flag name.
+type Describer interface {
+	Describe(key string) string
+}
+
+// Layered is implemented by providers that expand into several merge layers,
+// such as a directory of config fragments. Config merges each layer in
+// order, so a later layer overrides an earlier one.
+type Layered interface {
+	Layers() ([]Provider, error)
+}
+
+// ContextProvider is a provider whose Load can be cancelled or timed out,
+// such as a network-backed source. Register it with
+// configo.WithContextProvider.
+type ContextProvider interface {
+	Load(ctx context.Context) (map[string]any, error)
+}
+
+// WatchableProvider is implemented by providers backed by files. Config.Watch
+// watches every returned path, file or directory, and reloads when one
+// changes.
+type WatchableProvider interface {
+	Paths() []string
+}
+
+// NotifyingProvider is implemented by providers that detect their own
+// changes, such as a remote source with long polling. Config.Watch calls
+// Watch in its own goroutine; Watch calls notify on every change and returns
+// once ctx is cancelled.
+type NotifyingProvider interface {
+	Watch(ctx context.Context, notify func()) error
+}
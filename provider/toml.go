@@ -27,3 +27,15 @@ func (p *TOML) Load() (map[string]any, error) {
 	}
 	return raw, nil
 }
+
+func (p *TOML) Name() string {
+	return "toml"
+}
+
+func (p *TOML) Describe(string) string {
+	return p.Path
+}
+
+func (p *TOML) Paths() []string {
+	return []string{p.Path}
+}
@@ -27,3 +27,15 @@ func (p *YAML) Load() (map[string]any, error) {
 	}
 	return raw, nil
 }
+
+func (p *YAML) Name() string {
+	return "yaml"
+}
+
+func (p *YAML) Describe(string) string {
+	return p.Path
+}
+
+func (p *YAML) Paths() []string {
+	return []string{p.Path}
+}
@@ -0,0 +1,159 @@
+package configo
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+const secretPrefix = "secret://"
+
+// redacted replaces sensitive values wherever the config is printed.
+const redacted = "[REDACTED]"
+
+// Resolver resolves a secret reference to its value. For
+// secret://<scheme>/<ref>, Resolve receives <ref>.
+type Resolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// ResolverFunc adapts a function to the Resolver interface.
+type ResolverFunc func(ref string) (string, error)
+
+func (f ResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// WithResolver registers r for secret://<scheme>/... references, replacing
+// any resolver already registered for scheme. The file and env schemes are
+// registered by default.
+func WithResolver(scheme string, r Resolver) Option {
+	return func(c *Config) {
+		c.resolvers[scheme] = r
+	}
+}
+
+// FileResolver reads a secret from an absolute path, as in
+// secret://file/run/secrets/db_password. A trailing newline is trimmed.
+func FileResolver() Resolver {
+	return ResolverFunc(func(ref string) (string, error) {
+		data, err := os.ReadFile("/" + strings.TrimPrefix(ref, "/"))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	})
+}
+
+// EnvResolver reads a secret from an environment variable, as in
+// secret://env/DB_PASS. An unset variable is an error.
+func EnvResolver() Resolver {
+	return ResolverFunc(func(ref string) (string, error) {
+		v, ok := os.LookupEnv(ref)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", ref)
+		}
+		return v, nil
+	})
+}
+
+// CommandResolver runs a command and uses its trimmed standard output, as in
+// secret://cmd/pass show db. It is not registered by default because it lets
+// config files execute programs; opt in with WithResolver("cmd", CommandResolver()).
+func CommandResolver() Resolver {
+	return ResolverFunc(func(ref string) (string, error) {
+		args := strings.Fields(ref)
+		if len(args) == 0 {
+			return "", fmt.Errorf("empty command")
+		}
+		out, err := exec.Command(args[0], args[1:]...).Output()
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+	})
+}
+
+func defaultResolvers() map[string]Resolver {
+	return map[string]Resolver{
+		"file": FileResolver(),
+		"env":  EnvResolver(),
+	}
+}
+
+// resolveSecrets replaces every secret:// reference in data with its value
+// and marks the key as sensitive.
+func (c *Config) resolveSecrets(data map[string]any, sensitive map[string]bool) error {
+	for key, v := range data {
+		s, ok := v.(string)
+		if !ok || !strings.HasPrefix(s, secretPrefix) {
+			continue
+		}
+		scheme, ref, _ := strings.Cut(strings.TrimPrefix(s, secretPrefix), "/")
+		r, ok := c.resolvers[scheme]
+		if !ok {
+			return &SecretError{Key: key, Scheme: scheme, Err: fmt.Errorf("no resolver registered")}
+		}
+		val, err := r.Resolve(ref)
+		if err != nil {
+			return &SecretError{Key: key, Scheme: scheme, Err: err}
+		}
+		data[key] = val
+		sensitive[key] = true
+	}
+	return nil
+}
+
+// IsSensitive reports whether the value of key came from a secret or was
+// built from one, and is therefore redacted when printed.
+func (c *Config) IsSensitive(key string) bool {
+	return c.Snapshot().IsSensitive(key)
+}
+
+// IsSensitive reports whether the value of key is sensitive, like
+// Config.IsSensitive.
+func (s *Snapshot) IsSensitive(key string) bool {
+	return s.sensitive[s.fullKey(key)]
+}
+
+// Redacted returns a copy of the configuration data with sensitive values
+// replaced by a placeholder.
+func (c *Config) Redacted() map[string]any {
+	return c.Snapshot().Redacted()
+}
+
+// Redacted returns a copy of the snapshot's data with sensitive values
+// replaced by a placeholder.
+func (s *Snapshot) Redacted() map[string]any {
+	out := make(map[string]any)
+	s.each(func(k string, v any, sensitive bool) {
+		if sensitive {
+			v = redacted
+		}
+		out[k] = v
+	})
+	return out
+}
+
+// String formats the configuration as sorted key = value lines with
+// sensitive values redacted.
+func (c *Config) String() string {
+	return c.Snapshot().String()
+}
+
+// String formats the snapshot like Config.String.
+func (s *Snapshot) String() string {
+	data := s.Redacted()
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s = %v\n", k, data[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package configo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretResolvers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(path, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIGO_TEST_API_KEY", "k-123")
+
+	cfg := New(WithDefaults(map[string]any{
+		"database.user":     "app",
+		"database.password": "secret://file" + path,
+		"api.key":           "secret://env/CONFIGO_TEST_API_KEY",
+		"database.dsn":      "postgres://${database.user}:${database.password}@db/app",
+	}))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := MustGet[string](cfg, "database.password"); got != "s3cret" {
+		t.Errorf("database.password = %q, want s3cret", got)
+	}
+	if got := MustGet[string](cfg, "api.key"); got != "k-123" {
+		t.Errorf("api.key = %q, want k-123", got)
+	}
+	for _, key := range []string{"database.password", "api.key", "database.dsn"} {
+		if !cfg.IsSensitive(key) {
+			t.Errorf("%s should be sensitive", key)
+		}
+	}
+	if cfg.IsSensitive("database.user") {
+		t.Error("database.user should not be sensitive")
+	}
+
+	out := cfg.String()
+	if strings.Contains(out, "s3cret") || strings.Contains(out, "k-123") {
+		t.Errorf("String() leaks a secret:\n%s", out)
+	}
+	if !strings.Contains(out, "database.user = app") {
+		t.Errorf("String() missing plain value:\n%s", out)
+	}
+	if got := cfg.Redacted()["api.key"]; got != redacted {
+		t.Errorf("Redacted api.key = %v, want %s", got, redacted)
+	}
+}
+
+func TestSecretCustomResolver(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"token": "secret://vault/kv/app#token"}),
+		WithResolver("vault", ResolverFunc(func(ref string) (string, error) {
+			return "resolved:" + ref, nil
+		})),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := MustGet[string](cfg, "token"); got != "resolved:kv/app#token" {
+		t.Errorf("token = %q", got)
+	}
+}
+
+func TestSecretUnknownScheme(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{"password": "secret://cmd/pass show db"}))
+	err := cfg.Load()
+	var se *SecretError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected SecretError, got %v", err)
+	}
+	if se.Key != "password" || se.Scheme != "cmd" {
+		t.Errorf("SecretError = %+v", se)
+	}
+}
+
+func TestSecretCommandResolver(t *testing.T) {
+	cfg := New(
+		WithDefaults(map[string]any{"password": "secret://cmd/echo from-cmd"}),
+		WithResolver("cmd", CommandResolver()),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := MustGet[string](cfg, "password"); got != "from-cmd" {
+		t.Errorf("password = %q, want from-cmd", got)
+	}
+}
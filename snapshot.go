@@ -0,0 +1,104 @@
+package configo
+
+import "strings"
+
+// Getter is the read interface accepted by Get, GetOr and MustGet. It is
+// implemented by *Config and *Snapshot.
+type Getter interface {
+	Lookup(key string) (any, bool)
+}
+
+// Snapshot is an immutable, versioned view of the configuration as it was
+// after one Load or override change. Reads never take a lock, and every read
+// from the same Snapshot sees the same data, even while a reload runs.
+type Snapshot struct {
+	version   uint64
+	data      map[string]any
+	sources   map[string][]Source
+	sensitive map[string]bool
+	// parents holds every key that has nested keys below it, so that a
+	// lookup miss does not have to scan data.
+	parents   map[string]bool
+	normalize KeyNormalizer
+	prefix    string
+}
+
+var emptySnapshot = &Snapshot{}
+
+// Snapshot returns the current configuration. For a Sub view the snapshot
+// is scoped to the view's prefix.
+func (c *Config) Snapshot() *Snapshot {
+	s := c.base().snap.Load()
+	if s == nil {
+		s = emptySnapshot
+	}
+	return s.Sub(c.prefix)
+}
+
+// Version increases by one every time new data is published. It is 0
+// before the first Load.
+func (s *Snapshot) Version() uint64 {
+	return s.version
+}
+
+// Prefix returns the key prefix of a scoped snapshot, or "".
+func (s *Snapshot) Prefix() string {
+	return s.prefix
+}
+
+// Sub returns the snapshot scoped to prefix, like Config.Sub.
+func (s *Snapshot) Sub(prefix string) *Snapshot {
+	prefix = strings.Trim(prefix, ".")
+	if prefix == "" {
+		return s
+	}
+	scoped := *s
+	scoped.prefix = s.fullKey(prefix)
+	return &scoped
+}
+
+// Lookup returns the raw value stored for key. For a key with nested
+// values, such as a list or a map section, it returns them reassembled as a
+// []any or map[string]any. List items are addressed by index, as
+// "servers.0.host" or "servers[0].host". As in Unflatten, if one layer sets
+// both a scalar and nested keys for key, the nested keys win.
+func (s *Snapshot) Lookup(key string) (any, bool) {
+	fk := s.fullKey(key)
+	if s.parents[fk] {
+		return assemble(s.data, fk)
+	}
+	val, ok := s.data[fk]
+	return val, ok
+}
+
+// Data returns a copy of the snapshot's data.
+func (s *Snapshot) Data() map[string]any {
+	out := make(map[string]any)
+	s.each(func(k string, v any, _ bool) {
+		out[k] = v
+	})
+	return out
+}
+
+// fullKey converts a key relative to the snapshot's prefix into a
+// normalized root key.
+func (s *Snapshot) fullKey(key string) string {
+	if s.prefix != "" {
+		key = s.prefix + "." + key
+	}
+	return s.normalize.apply(key)
+}
+
+// each calls fn for every key in the snapshot, with keys relative to its
+// prefix.
+func (s *Snapshot) each(fn func(key string, val any, sensitive bool)) {
+	for k, v := range s.data {
+		rel, ok := k, true
+		if s.prefix != "" {
+			rel, ok = strings.CutPrefix(k, s.prefix+".")
+		}
+		if ok {
+			fn(rel, v, s.sensitive[k])
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package configo
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSnapshotIsImmutable(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{"server": map[string]any{"host": "a", "port": 80}}))
+	if cfg.Snapshot().Version() != 0 {
+		t.Errorf("version before Load = %d, want 0", cfg.Snapshot().Version())
+	}
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	snap := cfg.Snapshot()
+	if snap.Version() != 1 {
+		t.Errorf("version = %d, want 1", snap.Version())
+	}
+	if err := cfg.Set("server.host", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := MustGet[string](snap, "server.host"); got != "a" {
+		t.Errorf("old snapshot server.host = %q, want a", got)
+	}
+	if got := MustGet[string](cfg, "server.host"); got != "b" {
+		t.Errorf("config server.host = %q, want b", got)
+	}
+	if v := cfg.Snapshot().Version(); v != 2 {
+		t.Errorf("version after Set = %d, want 2", v)
+	}
+
+	type Server struct {
+		Host string `config:"host"`
+		Port int    `config:"port"`
+	}
+	var srv Server
+	if err := snap.Sub("server").Bind(&srv); err != nil {
+		t.Fatal(err)
+	}
+	if srv.Host != "a" || srv.Port != 80 {
+		t.Errorf("Bind from snapshot = %+v", srv)
+	}
+}
+
+func TestSnapshotConsistentUnderReload(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{"a": 0, "b": 0}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 200; i++ {
+			_ = cfg.Set("pair", map[string]any{"a": i, "b": i})
+		}
+	}()
+
+	type Pair struct {
+		A int `config:"pair.a"`
+		B int `config:"pair.b"`
+	}
+	for range 500 {
+		var p Pair
+		if err := cfg.Bind(&p); err != nil {
+			t.Fatal(err)
+		}
+		if p.A != p.B {
+			t.Fatalf("Bind mixed two versions: %+v", p)
+		}
+	}
+	wg.Wait()
+}
+
+func TestSnapshotLookupSections(t *testing.T) {
+	cfg := New(WithDefaults(map[string]any{
+		"database": map[string]any{"primary": map[string]any{"host": "db"}},
+		"servers":  []any{map[string]any{"host": "a"}},
+	}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	snap := cfg.Snapshot()
+	for _, key := range []string{"database", "database.primary", "servers", "servers.0", "servers[0].host"} {
+		if !snap.IsSet(key) {
+			t.Errorf("IsSet(%q) = false, want true", key)
+		}
+	}
+	for _, key := range []string{"missing", "database.replica", "datab", "servers.1", "servers.0.port"} {
+		if snap.IsSet(key) {
+			t.Errorf("IsSet(%q) = true, want false", key)
+		}
+	}
+	if got := MustGet[string](snap.Sub("database"), "primary.host"); got != "db" {
+		t.Errorf("Sub(database) primary.host = %q, want db", got)
+	}
+}
@@ -0,0 +1,69 @@
+package configo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devaloi/configo/provider"
+)
+
+// Source records where a configuration value came from.
+type Source struct {
+	Provider string // provider type, e.g. "yaml" or "env"
+	Location string // file path, env var or flag name, if known
+	Layer    int    // position of the provider in load order
+	Value    any
+}
+
+func (s Source) String() string {
+	if s.Location == "" {
+		return s.Provider
+	}
+	return fmt.Sprintf("%s (%s)", s.Provider, s.Location)
+}
+
+// Source returns the provider that supplied the effective value for key.
+func (c *Config) Source(key string) (Source, bool) {
+	return c.Snapshot().Source(key)
+}
+
+// Source returns the provider that supplied the value for key in the
+// snapshot.
+func (s *Snapshot) Source(key string) (Source, bool) {
+	layers := s.sources[s.fullKey(key)]
+	if len(layers) == 0 {
+		return Source{}, false
+	}
+	return layers[len(layers)-1], true
+}
+
+// Explain returns every layer that defined key, ordered from highest to
+// lowest precedence. The first entry is the one that won.
+func (c *Config) Explain(key string) []Source {
+	return c.Snapshot().Explain(key)
+}
+
+// Explain returns every layer that defined key in the snapshot, highest
+// precedence first.
+func (s *Snapshot) Explain(key string) []Source {
+	layers := s.sources[s.fullKey(key)]
+	out := make([]Source, len(layers))
+	for i, s := range layers {
+		out[len(layers)-1-i] = s
+	}
+	return out
+}
+
+func providerName(p any) string {
+	if n, ok := p.(provider.Named); ok {
+		return n.Name()
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", p), "*")
+}
+
+func describeKey(p any, key string) string {
+	if d, ok := p.(provider.Describer); ok {
+		return d.Describe(key)
+	}
+	return ""
+}
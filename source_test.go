@@ -0,0 +1,78 @@
+package configo
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestSourceReportsWinningProvider(t *testing.T) {
+	t.Setenv("SRC_DATABASE_HOST", "envhost")
+
+	cfg := New(
+		WithDefaults(map[string]any{"database.host": "defaulthost"}),
+		WithFile("testdata/config.yaml"),
+		WithEnvPrefix("SRC"),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	src, ok := cfg.Source("database.host")
+	if !ok {
+		t.Fatal("expected source for database.host")
+	}
+	if src.Provider != "env" || src.Location != "SRC_DATABASE_HOST" {
+		t.Errorf("source = %v, want env (SRC_DATABASE_HOST)", src)
+	}
+	if src.Value != "envhost" {
+		t.Errorf("value = %v, want envhost", src.Value)
+	}
+
+	src, ok = cfg.Source("server.port")
+	if !ok {
+		t.Fatal("expected source for server.port")
+	}
+	if src.Provider != "yaml" || src.Location != "testdata/config.yaml" {
+		t.Errorf("source = %v, want yaml (testdata/config.yaml)", src)
+	}
+
+	if _, ok := cfg.Source("missing"); ok {
+		t.Error("expected no source for missing key")
+	}
+}
+
+func TestExplainListsAllLayers(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("database.host", "", "host")
+	_ = fs.Parse([]string{"--database.host=flaghost"})
+
+	cfg := New(
+		WithDefaults(map[string]any{"database.host": "defaulthost"}),
+		WithFile("testdata/config.yaml"),
+		WithFlags(fs),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	layers := cfg.Explain("database.host")
+	if len(layers) != 3 {
+		t.Fatalf("expected 3 layers, got %d: %v", len(layers), layers)
+	}
+	want := []struct {
+		provider string
+		value    any
+	}{
+		{"flag", "flaghost"},
+		{"yaml", "db.example.com"},
+		{"defaults", "defaulthost"},
+	}
+	for i, w := range want {
+		if layers[i].Provider != w.provider || layers[i].Value != w.value {
+			t.Errorf("layer %d = %v %v, want %s %v", i, layers[i], layers[i].Value, w.provider, w.value)
+		}
+	}
+	if layers[0].Location != "--database.host" {
+		t.Errorf("flag location = %q, want --database.host", layers[0].Location)
+	}
+}
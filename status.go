@@ -0,0 +1,160 @@
+package configo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Status describes the health of the configuration.
+type Status struct {
+	// Version and Checksum identify the data currently served.
+	Version  uint64
+	Checksum string
+	// LastReload is when Load last published data from the providers.
+	LastReload time.Time
+	// LastError is the most recent Load failure, kept after later successes.
+	LastError     error
+	LastErrorTime time.Time
+	// ConsecutiveFailures counts Load failures since the last clean Load.
+	ConsecutiveFailures int
+}
+
+// Degraded reports whether the served configuration is stale because the
+// most recent reload failed.
+func (s Status) Degraded() bool {
+	return s.ConsecutiveFailures > 0
+}
+
+// Status returns the current reload status.
+func (c *Config) Status() Status {
+	r := c.base()
+	snap := r.Snapshot()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	st := r.status
+	st.Version = snap.Version()
+	st.Checksum = snap.Checksum()
+	return st
+}
+
+// OnReloadError registers a callback for reloads triggered by Watch that
+// fail, including partial failures under BestEffort or LastKnownGood. When
+// none is registered, failures go to the warning handler.
+func (c *Config) OnReloadError(fn func(error)) {
+	r := c.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onReloadError = append(r.onReloadError, fn)
+}
+
+// reloadFailed reports a failed Watch reload.
+func (c *Config) reloadFailed(err error) {
+	c.mu.RLock()
+	handlers := slices.Clone(c.onReloadError)
+	c.mu.RUnlock()
+	if len(handlers) == 0 {
+		c.warn(err)
+		return
+	}
+	for _, fn := range handlers {
+		fn(err)
+	}
+}
+
+// recordReload updates the status after a Load. A LoadError still
+// published data, from the providers that succeeded.
+func (c *Config) recordReload(err error) {
+	now := time.Now()
+	var le *LoadError
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil || errors.As(err, &le) {
+		c.status.LastReload = now
+	}
+	if err == nil {
+		c.status.ConsecutiveFailures = 0
+		return
+	}
+	c.status.LastError = err
+	c.status.LastErrorTime = now
+	c.status.ConsecutiveFailures++
+}
+
+// Checksum returns a SHA-256 digest of the snapshot's keys and values, which
+// changes whenever the effective configuration does.
+func (s *Snapshot) Checksum() string {
+	h := sha256.New()
+	for _, k := range s.Keys() {
+		fmt.Fprintf(h, "%s=%#v\n", k, s.data[s.fullKey(k)])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HealthHandler returns an http.Handler that reports the status as JSON.
+// It responds 200 with "ok", or with "degraded" while the served config is
+// stale because reloads are failing, and 503 with "unavailable" before any
+// config has been loaded. The last error is reported only by kind, since
+// its text may quote configuration values.
+func (c *Config) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		st := c.Status()
+		body := struct {
+			Status              string    `json:"status"`
+			Version             uint64    `json:"version"`
+			Checksum            string    `json:"checksum,omitempty"`
+			LastReload          time.Time `json:"last_reload,omitzero"`
+			LastError           string    `json:"last_error,omitempty"`
+			LastErrorTime       time.Time `json:"last_error_time,omitzero"`
+			ConsecutiveFailures int       `json:"consecutive_failures"`
+		}{
+			Status:              "ok",
+			Version:             st.Version,
+			LastReload:          st.LastReload,
+			LastErrorTime:       st.LastErrorTime,
+			ConsecutiveFailures: st.ConsecutiveFailures,
+		}
+		if st.Version > 0 {
+			body.Checksum = st.Checksum
+		}
+		if st.LastError != nil {
+			body.LastError = healthError(st.LastError)
+		}
+		code := http.StatusOK
+		switch {
+		case st.Version == 0:
+			body.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+		case st.Degraded():
+			body.Status = "degraded"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
+
+// healthError describes err by kind without its message.
+func healthError(err error) string {
+	switch {
+	case errors.As(err, new(*ValidationError)):
+		return "validation failed"
+	case errors.As(err, new(*ChangeRejectedError)):
+		return "change rejected"
+	case errors.As(err, new(*InterpolationError)):
+		return "interpolation failed"
+	case errors.As(err, new(*SecretError)):
+		return "secret resolution failed"
+	case errors.As(err, new(*DecryptionError)):
+		return "decryption failed"
+	case errors.As(err, new(*ProviderError)):
+		return "provider failed"
+	default:
+		return "load failed"
+	}
+}
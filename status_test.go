@@ -0,0 +1,153 @@
+package configo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatus(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("port: 8080\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := New(WithFile(path))
+
+	if st := cfg.Status(); st.Version != 0 || !st.LastReload.IsZero() {
+		t.Errorf("status before Load = %+v", st)
+	}
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	ok := cfg.Status()
+	if ok.Version != 1 || ok.LastReload.IsZero() || ok.Degraded() || len(ok.Checksum) != 64 {
+		t.Errorf("status after Load = %+v", ok)
+	}
+
+	if err := os.WriteFile(path, []byte("port: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_ = cfg.Load()
+	_ = cfg.Load()
+	st := cfg.Status()
+	if st.ConsecutiveFailures != 2 || st.LastError == nil || !st.Degraded() {
+		t.Errorf("status after failures = %+v", st)
+	}
+	if st.Version != ok.Version || st.Checksum != ok.Checksum || !st.LastReload.Equal(ok.LastReload) {
+		t.Error("failed reloads should keep serving the previous config")
+	}
+
+	if err := os.WriteFile(path, []byte("port: 9090\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	st = cfg.Status()
+	if st.ConsecutiveFailures != 0 || st.LastError == nil || st.Checksum == ok.Checksum {
+		t.Errorf("status after recovery = %+v", st)
+	}
+}
+
+func TestOnReloadError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("port: 8080\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := New(WithFile(path))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	cfg.OnReloadError(func(err error) { errs <- err })
+	if err := cfg.Watch(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cfg.StopWatch() }()
+
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("port: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("expected a reload error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reload error")
+	}
+	if got := MustGet[int](cfg, "port"); got != 8080 {
+		t.Errorf("port = %d, want previous 8080", got)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	cfg := New(WithProvider(&failingProvider{}), WithLoadPolicy(LastKnownGood))
+
+	check := func(wantCode int, wantStatus string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		cfg.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		if rec.Code != wantCode {
+			t.Errorf("code = %d, want %d", rec.Code, wantCode)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body["status"] != wantStatus {
+			t.Errorf("status = %v, want %s", body["status"], wantStatus)
+		}
+	}
+
+	check(http.StatusServiceUnavailable, "unavailable")
+	_ = cfg.Load()
+	check(http.StatusOK, "ok")
+	_ = cfg.Load()
+	check(http.StatusOK, "degraded")
+}
+
+func TestHealthHandlerHidesSecrets(t *testing.T) {
+	t.Setenv("CONFIGO_TEST_PASS", "hunter2")
+	cfg := New(
+		WithDefaults(map[string]any{"db.password": "secret://env/CONFIGO_TEST_PASS"}),
+		WithValidation(map[string]Rule{"db.password": {Regex: "^[0-9]+$"}}),
+	)
+	if err := cfg.Load(); err == nil {
+		t.Fatal("expected validation error")
+	} else if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("validation error leaked a secret: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	cfg.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	body := rec.Body.String()
+	if strings.Contains(body, "hunter2") {
+		t.Errorf("health body leaked a secret: %s", body)
+	}
+	if !strings.Contains(body, `"last_error":"validation failed"`) {
+		t.Errorf("body = %s, want last_error validation failed", body)
+	}
+}
+
+// failingProvider succeeds on its first Load and fails afterwards.
+type failingProvider struct {
+	calls int
+}
+
+func (p *failingProvider) Load() (map[string]any, error) {
+	p.calls++
+	if p.calls > 1 {
+		return nil, os.ErrNotExist
+	}
+	return map[string]any{"port": 8080}, nil
+}
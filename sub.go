@@ -0,0 +1,51 @@
+package configo
+
+import "strings"
+
+// Sub returns a view of the configuration scoped to prefix, so that
+// Get[string](sub, "host") reads "<prefix>.host". Bind, Validate, Data and
+// OnChange work relative to the prefix, and the view follows reloads of the
+// parent. Load, Watch and StopWatch act on the parent.
+func (c *Config) Sub(prefix string) *Config {
+	prefix = strings.Trim(prefix, ".")
+	if prefix == "" {
+		return c
+	}
+	return &Config{root: c.base(), prefix: c.fullKey(prefix)}
+}
+
+// Prefix returns the key prefix of a Sub view, or "" for a root Config.
+func (c *Config) Prefix() string {
+	return c.prefix
+}
+
+// base returns the Config that owns the data: c itself, or the parent of a
+// Sub view.
+func (c *Config) base() *Config {
+	if c.root != nil {
+		return c.root
+	}
+	return c
+}
+
+// fullKey converts a key relative to a Sub view into a normalized root key.
+func (c *Config) fullKey(key string) string {
+	if c.prefix != "" {
+		key = c.prefix + "." + key
+	}
+	return c.base().normalizer.apply(key)
+}
+
+// relKey converts a root key into a key relative to c, reporting false if
+// key lies outside the view.
+func (c *Config) relKey(key string) (string, bool) {
+	if c.prefix == "" {
+		return key, true
+	}
+	return strings.CutPrefix(key, c.prefix+".")
+}
+
+// Lookup returns the raw value stored for key in the current snapshot.
+func (c *Config) Lookup(key string) (any, bool) {
+	return c.Snapshot().Lookup(key)
+}
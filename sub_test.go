@@ -0,0 +1,104 @@
+package configo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSubGetAndData(t *testing.T) {
+	cfg := newTestConfig(map[string]any{
+		"database": map[string]any{
+			"host": "db.example.com",
+			"port": 5432,
+			"pool": map[string]any{"size": 10},
+		},
+		"server": map[string]any{"host": "localhost"},
+	})
+
+	db := cfg.Sub("database")
+	if got := MustGet[string](db, "host"); got != "db.example.com" {
+		t.Errorf("host = %q, want db.example.com", got)
+	}
+	if db.Prefix() != "database" {
+		t.Errorf("Prefix = %q, want database", db.Prefix())
+	}
+
+	data := db.Data()
+	if len(data) != 3 {
+		t.Errorf("expected 3 keys, got %v", data)
+	}
+	if _, ok := data["server.host"]; ok {
+		t.Error("sub view should not include keys outside its prefix")
+	}
+
+	pool := db.Sub("pool")
+	if got := MustGet[int](pool, "size"); got != 10 {
+		t.Errorf("pool size = %d, want 10", got)
+	}
+	if pool.Prefix() != "database.pool" {
+		t.Errorf("nested Prefix = %q, want database.pool", pool.Prefix())
+	}
+}
+
+func TestSubBindAndValidate(t *testing.T) {
+	cfg := newTestConfig(map[string]any{
+		"database": map[string]any{"host": "db.example.com", "port": 70000},
+	})
+	db := cfg.Sub("database")
+
+	type DB struct {
+		Host string `config:"host"`
+		Port int    `config:"port" validate:"max=65535"`
+		Name string `config:"name" default:"app"`
+	}
+	var d DB
+	if err := db.Bind(&d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Host != "db.example.com" || d.Port != 70000 || d.Name != "app" {
+		t.Errorf("Bind = %+v", d)
+	}
+	if err := db.ValidateStruct(d); err == nil {
+		t.Error("expected validation error for port")
+	}
+}
+
+func TestSubFollowsReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("database:\n  host: first\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := New(WithFile(path))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	db := cfg.Sub("database")
+
+	changed := make(chan *Config, 1)
+	db.OnChange(func(c *Config) { changed <- c })
+	if err := db.Watch(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cfg.StopWatch() }()
+
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("database:\n  host: second\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-changed:
+		if got != db {
+			t.Error("OnChange on a sub view should receive the view")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+	if got := MustGet[string](db, "host"); got != "second" {
+		t.Errorf("host = %q, want second", got)
+	}
+}
@@ -20,14 +20,15 @@ type Rule struct {
 // Validate checks config values against the given rules.
 // All errors are collected into a ValidationError.
 func (c *Config) Validate(rules map[string]Rule) error {
-	var errs []FieldError
-
-	c.mu.RLock()
-	data := c.data
-	c.mu.RUnlock()
+	return c.Snapshot().Validate(rules)
+}
 
+// Validate checks the snapshot's values against the given rules, like
+// Config.Validate.
+func (s *Snapshot) Validate(rules map[string]Rule) error {
+	var errs []FieldError
 	for key, rule := range rules {
-		val, ok := data[key]
+		val, ok := s.Lookup(key)
 
 		if rule.Required && !ok {
 			errs = append(errs, FieldError{Field: key, Message: "required"})
@@ -36,34 +37,40 @@ func (c *Config) Validate(rules map[string]Rule) error {
 		if !ok {
 			continue
 		}
+		sensitive := s.IsSensitive(key)
+		shown := func(v any) string {
+			if sensitive {
+				return redacted
+			}
+			return fmt.Sprintf("%v", v)
+		}
 
 		if rule.Min != nil || rule.Max != nil {
 			n, err := toFloat64(val)
 			if err != nil {
-				errs = append(errs, FieldError{Field: key, Message: fmt.Sprintf("cannot convert to number: %v", err)})
+				errs = append(errs, FieldError{Field: key, Message: redactValue(fmt.Sprintf("cannot convert to number: %v", err), val, sensitive)})
 			} else {
 				if rule.Min != nil && n < *rule.Min {
-					errs = append(errs, FieldError{Field: key, Message: fmt.Sprintf("value %v is less than min %v", n, *rule.Min)})
+					errs = append(errs, FieldError{Field: key, Message: fmt.Sprintf("value %s is less than min %v", shown(n), *rule.Min)})
 				}
 				if rule.Max != nil && n > *rule.Max {
-					errs = append(errs, FieldError{Field: key, Message: fmt.Sprintf("value %v is greater than max %v", n, *rule.Max)})
+					errs = append(errs, FieldError{Field: key, Message: fmt.Sprintf("value %s is greater than max %v", shown(n), *rule.Max)})
 				}
 			}
 		}
 
 		if rule.Regex != "" {
-			s := fmt.Sprintf("%v", val)
 			re, err := regexp.Compile(rule.Regex)
 			if err != nil {
 				errs = append(errs, FieldError{Field: key, Message: fmt.Sprintf("invalid regex: %v", err)})
-			} else if !re.MatchString(s) {
-				errs = append(errs, FieldError{Field: key, Message: fmt.Sprintf("value %q does not match pattern %q", s, rule.Regex)})
+			} else if !re.MatchString(fmt.Sprintf("%v", val)) {
+				errs = append(errs, FieldError{Field: key, Message: fmt.Sprintf("value %q does not match pattern %q", shown(val), rule.Regex)})
 			}
 		}
 
 		if rule.Custom != nil {
 			if err := rule.Custom(val); err != nil {
-				errs = append(errs, FieldError{Field: key, Message: err.Error()})
+				errs = append(errs, FieldError{Field: key, Message: redactValue(err.Error(), val, sensitive)})
 			}
 		}
 	}
@@ -74,9 +81,25 @@ func (c *Config) Validate(rules map[string]Rule) error {
 	return nil
 }
 
+// redactValue replaces the value's text in a message from a custom rule or
+// conversion error when the key is sensitive.
+func redactValue(msg string, val any, sensitive bool) string {
+	raw := fmt.Sprintf("%v", val)
+	if !sensitive || raw == "" {
+		return msg
+	}
+	return strings.ReplaceAll(msg, raw, redacted)
+}
+
 // ValidateStruct validates a struct using `validate` tags.
 // Supported tags: required, min=N, max=N, regex=PATTERN.
 func (c *Config) ValidateStruct(target any) error {
+	return c.Snapshot().ValidateStruct(target)
+}
+
+// ValidateStruct validates the snapshot using a struct's `validate` tags,
+// like Config.ValidateStruct.
+func (s *Snapshot) ValidateStruct(target any) error {
 	v := reflect.ValueOf(target)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -88,7 +111,7 @@ func (c *Config) ValidateStruct(target any) error {
 	rules := make(map[string]Rule)
 	buildRulesFromStruct(v.Type(), rules)
 
-	return c.Validate(rules)
+	return s.Validate(rules)
 }
 
 func buildRulesFromStruct(t reflect.Type, rules map[string]Rule) {
@@ -0,0 +1,20 @@
+package configo
+
+import "log/slog"
+
+// WithWarningHandler sets the function that receives non-fatal problems
+// found while merging, such as key collisions. By default they are logged
+// with slog at warn level.
+func WithWarningHandler(fn func(error)) Option {
+	return func(c *Config) {
+		c.onWarning = fn
+	}
+}
+
+func (c *Config) warn(err error) {
+	if c.onWarning != nil {
+		c.onWarning(err)
+		return
+	}
+	slog.Warn("configo: " + err.Error())
+}
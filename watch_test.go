@@ -0,0 +1,161 @@
+package configo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchAllFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	local := filepath.Join(dir, "local.yaml")
+	env := filepath.Join(dir, ".env")
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(base, "name: base\nport: 80\n")
+	write(local, "port: 8080\n")
+	write(env, "DEBUG=false\n")
+
+	cfg := New(WithFile(base), WithFile(local), WithDotEnv(env))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	var reloads atomic.Int32
+	cfg.OnChange(func(*Config) { reloads.Add(1) })
+	if err := cfg.Watch(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cfg.StopWatch() }()
+
+	time.Sleep(100 * time.Millisecond)
+	write(base, "name: changed\nport: 80\n")
+	write(local, "port: 9090\n")
+	write(env, "DEBUG=true\n")
+
+	deadline := time.Now().Add(3 * time.Second)
+	for reloads.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	time.Sleep(700 * time.Millisecond)
+
+	if n := reloads.Load(); n != 1 {
+		t.Errorf("expected 1 coalesced reload, got %d", n)
+	}
+	if got := MustGet[string](cfg, "name"); got != "changed" {
+		t.Errorf("name = %q, want changed", got)
+	}
+	if got := MustGet[int](cfg, "port"); got != 9090 {
+		t.Errorf("port = %d, want 9090", got)
+	}
+	if got := MustGet[bool](cfg, "debug"); !got {
+		t.Error("debug = false, want true from the .env file")
+	}
+}
+
+// notifyingProvider reports a change whenever its channel receives.
+type notifyingProvider struct {
+	value   atomic.Value
+	changes chan struct{}
+}
+
+func (p *notifyingProvider) Load() (map[string]any, error) {
+	return map[string]any{"mode": p.value.Load()}, nil
+}
+
+func (p *notifyingProvider) Watch(ctx context.Context, notify func()) error {
+	for {
+		select {
+		case <-p.changes:
+			notify()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func TestWatchNotifyingProvider(t *testing.T) {
+	p := &notifyingProvider{changes: make(chan struct{})}
+	p.value.Store("blue")
+	cfg := New(WithProvider(p))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	changed := make(chan struct{}, 1)
+	cfg.OnChange(func(*Config) { changed <- struct{}{} })
+	if err := cfg.Watch(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cfg.StopWatch() }()
+
+	p.value.Store("green")
+	p.changes <- struct{}{}
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+	if got := MustGet[string](cfg, "mode"); got != "green" {
+		t.Errorf("mode = %q, want green", got)
+	}
+}
+
+// fakeWatcher is a watcher.Backend that tests drive by calling change.
+type fakeWatcher struct {
+	paths    []string
+	onChange []func()
+	started  bool
+	stopped  bool
+}
+
+func (w *fakeWatcher) Add(path string) error { w.paths = append(w.paths, path); return nil }
+func (w *fakeWatcher) OnChange(fn func())    { w.onChange = append(w.onChange, fn) }
+func (w *fakeWatcher) Trigger()              { w.change() }
+func (w *fakeWatcher) Start() error          { w.started = true; return nil }
+func (w *fakeWatcher) Stop() error           { w.stopped = true; return nil }
+
+func (w *fakeWatcher) change() {
+	for _, fn := range w.onChange {
+		fn()
+	}
+}
+
+func TestWithWatcherBackend(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("port: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fw := &fakeWatcher{}
+	cfg := New(WithFile(path), WithWatcher(fw))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	var ports []any
+	cfg.OnKeyChange("port", func(ch Change) { ports = append(ports, ch.New) })
+	if err := cfg.Watch(); err != nil {
+		t.Fatal(err)
+	}
+	if !fw.started || len(fw.paths) != 1 || fw.paths[0] != path {
+		t.Fatalf("backend not set up: %+v", fw)
+	}
+
+	if err := os.WriteFile(path, []byte("port: 8080\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fw.change()
+	if len(ports) != 1 || ports[0] != 8080 {
+		t.Errorf("port changes = %v, want [8080]", ports)
+	}
+
+	if err := cfg.StopWatch(); err != nil || !fw.stopped {
+		t.Errorf("StopWatch should stop the backend, err = %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package watcher
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Poller is a Backend that checks its paths on an interval instead of using
+// file system notifications, for NFS and SMB mounts and container overlay
+// file systems where fsnotify misses changes. A file counts as changed when
+// its modification time, size or content hash differ; a directory when any
+// entry is added, removed or changed. Changes found in the same check, and
+// Trigger calls since the last check, are coalesced into one call.
+type Poller struct {
+	interval time.Duration
+	paths    []string
+	state    map[string]fileState
+	onChange []func()
+	pending  bool // Trigger called since the last check
+	done     chan struct{}
+	mu       sync.Mutex
+}
+
+// fileState is what a Poller remembers about a path between checks.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+var _ Backend = (*Poller)(nil)
+
+// Poll returns a Poller that checks for changes every interval, or every
+// two seconds if interval is zero.
+func Poll(interval time.Duration) *Poller {
+	if interval == 0 {
+		interval = 2 * time.Second
+	}
+	return &Poller{
+		interval: interval,
+		state:    make(map[string]fileState),
+		done:     make(chan struct{}),
+	}
+}
+
+// Add polls another file or directory. The path need not exist yet.
+func (p *Poller) Add(path string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paths = append(p.paths, path)
+	p.state[path] = statPath(path)
+	return nil
+}
+
+// OnChange registers a callback that fires when a polled path changes.
+func (p *Poller) OnChange(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onChange = append(p.onChange, fn)
+}
+
+// Trigger reports a change detected elsewhere. Subscribers are called at
+// the next check.
+func (p *Poller) Trigger() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending = true
+}
+
+// Start begins polling in the background until Stop is called.
+func (p *Poller) Start() error {
+	go p.loop()
+	return nil
+}
+
+func (p *Poller) loop() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if p.check() {
+				p.fire()
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// check refreshes the state of every path and reports whether any changed.
+func (p *Poller) check() bool {
+	p.mu.Lock()
+	paths := append([]string(nil), p.paths...)
+	p.mu.Unlock()
+
+	current := make(map[string]fileState, len(paths))
+	for _, path := range paths {
+		current[path] = statPath(path)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := p.pending
+	p.pending = false
+	for path, st := range current {
+		if p.state[path] != st {
+			changed = true
+		}
+		p.state[path] = st
+	}
+	return changed
+}
+
+func (p *Poller) fire() {
+	p.mu.Lock()
+	handlers := make([]func(), len(p.onChange))
+	copy(handlers, p.onChange)
+	p.mu.Unlock()
+	for _, fn := range handlers {
+		fn()
+	}
+}
+
+// Stop stops polling.
+func (p *Poller) Stop() error {
+	close(p.done)
+	return nil
+}
+
+// statPath returns the state of a file, following symlinks, or of a
+// directory's direct entries. Like the fsnotify backend it is not
+// recursive: a subdirectory only contributes its own size and modification
+// time, and its contents are never read.
+func statPath(path string) fileState {
+	return stat(path, true)
+}
+
+func stat(path string, listDir bool) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	st := fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+	if info.IsDir() && !listDir {
+		return st
+	}
+	h := sha256.New()
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return st
+		}
+		for _, e := range entries {
+			es := stat(filepath.Join(path, e.Name()), false)
+			fmt.Fprintf(h, "%s\x00%v\x00%d\x00%x\n", e.Name(), es.modTime.UnixNano(), es.size, es.hash)
+		}
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return st
+		}
+		_, _ = io.Copy(h, f)
+		_ = f.Close()
+	}
+	copy(st.hash[:], h.Sum(nil))
+	return st
+}
@@ -0,0 +1,98 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startPolling starts a Poller on paths and returns the number of change
+// callbacks so far.
+func startPolling(t *testing.T, paths ...string) (*Poller, *atomic.Int32) {
+	t.Helper()
+	p := Poll(20 * time.Millisecond)
+	for _, path := range paths {
+		if err := p.Add(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var called atomic.Int32
+	p.OnChange(func() { called.Add(1) })
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = p.Stop() })
+	return p, &called
+}
+
+func TestPollDetectsContentChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "key: 1")
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, called := startPolling(t, path)
+
+	// Same size and modification time, as on a coarse-grained NFS mount:
+	// only the content hash differs.
+	writeFile(t, path, "key: 2")
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	waitCalls(t, called, 1)
+
+	waitCalls(t, called, 1) // no further changes, no further calls
+}
+
+func TestPollCreateRemoveAndDirectory(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.local.yaml")
+	confd := filepath.Join(dir, "conf.d")
+	if err := os.Mkdir(confd, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, called := startPolling(t, path, confd)
+
+	writeFile(t, path, "key: 1")
+	waitCalls(t, called, 1)
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	waitCalls(t, called, 2)
+	writeFile(t, filepath.Join(confd, "10-base.yaml"), "key: 1")
+	waitCalls(t, called, 3)
+}
+
+func TestPollDirectoryIsNotRecursive(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, ".git")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(sub, "index")
+	writeFile(t, nested, "a")
+	_, called := startPolling(t, dir)
+
+	// Rewriting a file in a subdirectory leaves the subdirectory's own
+	// metadata alone, so the poller must not notice it.
+	writeFile(t, nested, "b")
+	waitCalls(t, called, 0)
+
+	writeFile(t, filepath.Join(dir, "config.yaml"), "key: 1")
+	waitCalls(t, called, 1)
+}
+
+func TestPollCoalescesTrigger(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "key: 1")
+	p, called := startPolling(t, path)
+
+	p.Trigger()
+	p.Trigger()
+	waitCalls(t, called, 1)
+}
@@ -1,82 +1,198 @@
 package watcher
 
 import (
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
 
-// Watcher monitors a file for changes and calls subscribers after debounce.
+// Backend watches files for changes and notifies subscribers. Watcher,
+// which uses file system notifications, and Poller implement it; tests can
+// supply a fake to drive reloads directly.
+type Backend interface {
+	// Add watches another file or directory.
+	Add(path string) error
+	// OnChange registers a callback for changes.
+	OnChange(fn func())
+	// Trigger reports a change detected outside the backend.
+	Trigger()
+	// Start begins watching in the background.
+	Start() error
+	// Stop stops watching. A stopped backend cannot be restarted.
+	Stop() error
+}
+
+var _ Backend = (*Watcher)(nil)
+
+// Watcher monitors files for changes and calls subscribers after debounce.
+// Changes to any watched path, and calls to Trigger, that arrive within the
+// debounce interval are coalesced into one call.
+//
+// A file is watched through its parent directory, so that saves which
+// replace the file (write to a temporary file and rename, or remove and
+// recreate) keep being noticed, and a file that does not exist yet is
+// picked up once created. Symlinked files, such as Kubernetes ConfigMap
+// mounts that swap a ..data symlink, are resolved on every event and their
+// target directory is watched too.
 type Watcher struct {
-	path      string
+	targets   []*target
+	dirs      map[string]bool // directories added to fsWatcher
 	debounce  time.Duration
 	onChange  []func()
 	fsWatcher *fsnotify.Watcher
+	trigger   chan struct{}
 	done      chan struct{}
 	mu        sync.Mutex
 }
 
-// New creates a Watcher for the given file path.
+// target is one path passed to New or Add.
+type target struct {
+	path     string // absolute, cleaned
+	isDir    bool
+	resolved string // path with symlinks evaluated; path if it cannot be
+}
+
+func newTarget(path string) *target {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	t := &target{path: filepath.Clean(path)}
+	if info, err := os.Stat(t.path); err == nil && info.IsDir() {
+		t.isDir = true
+	}
+	t.resolved = resolve(t.path)
+	return t
+}
+
+func resolve(path string) string {
+	if r, err := filepath.EvalSymlinks(path); err == nil {
+		return r
+	}
+	return path
+}
+
+// watchDirs returns the directories whose events can affect the target.
+func (t *target) watchDirs() []string {
+	if t.isDir {
+		return []string{t.path}
+	}
+	dirs := []string{filepath.Dir(t.path)}
+	if rd := filepath.Dir(t.resolved); rd != dirs[0] {
+		dirs = append(dirs, rd)
+	}
+	return dirs
+}
+
+// New creates a Watcher for the given file or directory path. An empty
+// path watches nothing until Add is called.
 func New(path string, debounce time.Duration) *Watcher {
 	if debounce == 0 {
 		debounce = 500 * time.Millisecond
 	}
-	return &Watcher{
-		path:     path,
+	w := &Watcher{
+		dirs:     make(map[string]bool),
 		debounce: debounce,
+		trigger:  make(chan struct{}, 1),
 		done:     make(chan struct{}),
 	}
+	if path != "" {
+		w.targets = append(w.targets, newTarget(path))
+	}
+	return w
+}
+
+// Add watches another file or directory. The file need not exist, but its
+// directory must. Paths added after Start are watched immediately.
+func (w *Watcher) Add(path string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t := newTarget(path)
+	w.targets = append(w.targets, t)
+	if w.fsWatcher != nil {
+		return w.arm(t)
+	}
+	return nil
+}
+
+// arm adds the target's directories to fsWatcher. The caller must hold mu.
+func (w *Watcher) arm(t *target) error {
+	for _, dir := range t.watchDirs() {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.fsWatcher.Add(dir); err != nil {
+			return err
+		}
+		w.dirs[dir] = true
+	}
+	return nil
+}
+
+// Trigger reports a change detected outside the file system, such as by a
+// remote provider. It is debounced together with file events.
+func (w *Watcher) Trigger() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
 }
 
-// OnChange registers a callback that fires when the watched file changes.
+// OnChange registers a callback that fires when a watched path changes.
 func (w *Watcher) OnChange(fn func()) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.onChange = append(w.onChange, fn)
 }
 
-// Start begins watching the file. It blocks until Stop is called.
+// Start begins watching in the background until Stop is called.
 func (w *Watcher) Start() error {
 	fw, err := fsnotify.NewWatcher()
 	if err != nil {
 		return err
 	}
-	w.fsWatcher = fw
 
-	if err := fw.Add(w.path); err != nil {
-		_ = fw.Close()
-		return err
+	w.mu.Lock()
+	w.fsWatcher = fw
+	for _, t := range w.targets {
+		if err := w.arm(t); err != nil {
+			w.fsWatcher = nil
+			w.mu.Unlock()
+			_ = fw.Close()
+			return err
+		}
 	}
+	w.mu.Unlock()
 
-	go w.loop()
+	go w.loop(fw)
 	return nil
 }
 
-func (w *Watcher) loop() {
+func (w *Watcher) loop(fw *fsnotify.Watcher) {
 	var timer *time.Timer
+	schedule := func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(w.debounce, w.fire)
+	}
 	for {
 		select {
-		case event, ok := <-w.fsWatcher.Events:
+		case event, ok := <-fw.Events:
 			if !ok {
 				return
 			}
-			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+			if event.Op == fsnotify.Chmod {
 				continue
 			}
-			if timer != nil {
-				timer.Stop()
+			if w.affects(event) {
+				schedule()
 			}
-			timer = time.AfterFunc(w.debounce, func() {
-				w.mu.Lock()
-				handlers := make([]func(), len(w.onChange))
-				copy(handlers, w.onChange)
-				w.mu.Unlock()
-				for _, fn := range handlers {
-					fn()
-				}
-			})
-		case _, ok := <-w.fsWatcher.Errors:
+		case <-w.trigger:
+			schedule()
+		case _, ok := <-fw.Errors:
 			if !ok {
 				return
 			}
@@ -89,9 +205,54 @@ func (w *Watcher) loop() {
 	}
 }
 
-// Stop stops watching the file.
+// affects reports whether event changes any target. It also follows
+// symlink swaps: a target whose resolved path changed is re-armed on its
+// new directory.
+func (w *Watcher) affects(event fsnotify.Event) bool {
+	name := filepath.Clean(event.Name)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 && w.dirs[name] {
+		// fsnotify drops the watch on a removed directory.
+		delete(w.dirs, name)
+	}
+
+	changed := false
+	for _, t := range w.targets {
+		if t.isDir {
+			if name == t.path || filepath.Dir(name) == t.path {
+				changed = true
+			}
+			continue
+		}
+		if name == t.path || name == t.resolved {
+			changed = true
+		}
+		if r := resolve(t.path); r != t.resolved {
+			t.resolved = r
+			_ = w.arm(t)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (w *Watcher) fire() {
+	w.mu.Lock()
+	handlers := make([]func(), len(w.onChange))
+	copy(handlers, w.onChange)
+	w.mu.Unlock()
+	for _, fn := range handlers {
+		fn()
+	}
+}
+
+// Stop stops watching.
 func (w *Watcher) Stop() error {
 	close(w.done)
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	if w.fsWatcher != nil {
 		return w.fsWatcher.Close()
 	}
@@ -74,3 +74,177 @@ func TestWatcherDebounce(t *testing.T) {
 		t.Errorf("expected debounce to limit calls, got %d", c)
 	}
 }
+
+func TestWatcherCoalescesPathsAndTriggers(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.yaml")
+	b := filepath.Join(dir, "b.yaml")
+	for _, path := range []string{a, b} {
+		if err := os.WriteFile(path, []byte("key: 1"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := New("", 200*time.Millisecond)
+	if err := w.Add(a); err != nil {
+		t.Fatal(err)
+	}
+	var called atomic.Int32
+	w.OnChange(func() { called.Add(1) })
+	if err := w.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Stop() }()
+	if err := w.Add(b); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(a, []byte("key: 2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w.Trigger()
+	if err := os.WriteFile(b, []byte("key: 2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(600 * time.Millisecond)
+
+	if c := called.Load(); c != 1 {
+		t.Errorf("expected 1 coalesced call, got %d", c)
+	}
+}
+
+// startCounting starts a watcher on path with a short debounce and returns
+// the number of change callbacks so far.
+func startCounting(t *testing.T, path string) *atomic.Int32 {
+	t.Helper()
+	w := New(path, 50*time.Millisecond)
+	var called atomic.Int32
+	w.OnChange(func() { called.Add(1) })
+	if err := w.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = w.Stop() })
+	time.Sleep(50 * time.Millisecond)
+	return &called
+}
+
+// waitCalls waits until called reaches want, then checks it settles there.
+func waitCalls(t *testing.T, called *atomic.Int32, want int32) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for called.Load() < want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if got := called.Load(); got != want {
+		t.Fatalf("expected %d calls, got %d", want, got)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherRenameSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "key: 1")
+	called := startCounting(t, path)
+
+	// vim and JetBrains write a temporary file and rename it over the target.
+	for i := range 2 {
+		tmp := filepath.Join(dir, ".config.yaml.tmp")
+		writeFile(t, tmp, "key: 2")
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatal(err)
+		}
+		waitCalls(t, called, int32(i+1))
+	}
+}
+
+func TestWatcherRemoveAndRecreate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "key: 1")
+	called := startCounting(t, path)
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, "key: 2")
+	waitCalls(t, called, 1)
+
+	// The watch survives the remove.
+	writeFile(t, path, "key: 3")
+	waitCalls(t, called, 2)
+}
+
+func TestWatcherMissingFileCreated(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.local.yaml")
+	called := startCounting(t, path)
+
+	writeFile(t, path, "key: 1")
+	waitCalls(t, called, 1)
+}
+
+func TestWatcherIgnoresSiblings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "key: 1")
+	called := startCounting(t, path)
+
+	writeFile(t, filepath.Join(dir, "other.yaml"), "key: 1")
+	waitCalls(t, called, 0)
+}
+
+func TestWatcherConfigMapSymlinkSwap(t *testing.T) {
+	// Kubernetes mounts a ConfigMap as:
+	//   config.yaml -> ..data/config.yaml
+	//   ..data      -> ..2024_01_01
+	// and updates it by writing a new timestamped directory, then atomically
+	// renaming a new ..data symlink over the old one.
+	dir := t.TempDir()
+	version := func(name, content string) {
+		t.Helper()
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, name, "config.yaml"), content)
+	}
+	swap := func(name string) {
+		t.Helper()
+		tmp := filepath.Join(dir, "..data_tmp")
+		if err := os.Symlink(name, tmp); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, filepath.Join(dir, "..data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	version("..2024_01_01", "key: 1")
+	if err := os.Symlink("..2024_01_01", filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.Symlink(filepath.Join("..data", "config.yaml"), path); err != nil {
+		t.Fatal(err)
+	}
+	called := startCounting(t, path)
+
+	version("..2024_01_02", "key: 2")
+	swap("..2024_01_02")
+	if err := os.RemoveAll(filepath.Join(dir, "..2024_01_01")); err != nil {
+		t.Fatal(err)
+	}
+	waitCalls(t, called, 1)
+
+	version("..2024_01_03", "key: 3")
+	swap("..2024_01_03")
+	waitCalls(t, called, 2)
+}